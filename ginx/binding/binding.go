@@ -6,14 +6,20 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/hchicken/pkg-go/ginx/response"
-	"github.com/pkg/errors"
+	"github.com/hchicken/pkg-go/ginx/validator"
 )
 
-// handleError 处理错误并返回
+// handleError 处理错误并返回，校验错误按 Accept-Language 翻译为字段错误列表，
+// 返回 response.ErrInvalidParams，消息为第一个字段的错误信息
 func handleError(c *gin.Context, err error, message string) error {
-	if err != nil {
-		response.Json(c, response.Error(errors.Wrapf(err, message)))
+	if err == nil {
+		return nil
 	}
+	if fields := validator.Translate(err, validator.Lang(c.GetHeader("Accept-Language"))); len(fields) > 0 {
+		response.ErrorResponse(c, response.ErrInvalidParams.WithMessage(fields[0].Message).WithFields(fields...).Wrap(err))
+		return err
+	}
+	response.ErrorResponse(c, response.ErrInvalidParams.WithMessage(message).Wrap(err))
 	return err
 }
 
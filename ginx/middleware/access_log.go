@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLog 访问日志中间件，通过 logx 输出方法、路径、状态码、耗时、响应字节数和客户端IP，
+// 5xx 以error级别、4xx 以warn级别输出，其余为info级别
+func AccessLog(opts ...Option) gin.HandlerFunc {
+	options := newOptions(opts...)
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if options.skipPaths[path] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		status := c.Writer.Status()
+		fields := logrus.Fields{
+			"method":    c.Request.Method,
+			"path":      path,
+			"query":     c.Request.URL.RawQuery,
+			"status":    status,
+			"latency":   fmt.Sprintf("%.3fms", float64(latency.Nanoseconds())/1e6),
+			"bytes":     c.Writer.Size(),
+			"client_ip": c.ClientIP(),
+		}
+		if id := GetRequestID(c); id != "" {
+			fields["trace_id"] = id
+		}
+		entry := options.logger.WithFields(fields)
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("error", c.Errors.String())
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("access")
+		case status >= http.StatusBadRequest:
+			entry.Warn("access")
+		default:
+			entry.Info("access")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/hchicken/pkg-go/jwtx"
+)
+
+const (
+	ClaimsKey = "jwt_claims" // gin.Context 中 JWT claims 的key
+	RolesKey  = "jwt_roles"  // gin.Context 中用户角色的key
+)
+
+// claimsKey 请求上下文中 JWT claims 的key
+type claimsKey struct{}
+
+// Auth JWT认证中间件，从 Authorization: Bearer <token> 中提取token并通过 jwtx 校验，
+// claims 保存到 gin.Context 和请求上下文；token缺失或无效时返回 401 和 response.ErrUnauthorized。
+// PublicPaths 中的路径不要求token，携带有效token时仍会解析。
+// 未设置 Secret、TokenParser 且未调用 jwtx.SetSecret 时 panic，不允许使用内置默认密钥
+func Auth(opts ...Option) gin.HandlerFunc {
+	options := newOptions(opts...)
+	if options.parser == nil {
+		if !jwtx.SecretConfigured() {
+			panic("middleware: jwt secret is not configured, call jwtx.SetSecret or use middleware.Secret")
+		}
+		options.parser = jwtx.ParseToken
+	}
+	return func(c *gin.Context) {
+		public := options.isPublic(c.Request.URL.Path)
+		token := bearerToken(c)
+		if token == "" {
+			if public {
+				c.Next()
+				return
+			}
+			response.ErrorResponse(c, response.ErrUnauthorized.WithMessage("未登录或token缺失"))
+			return
+		}
+
+		claims, err := options.parser(token)
+		if err != nil || claims == nil {
+			if public {
+				c.Next()
+				return
+			}
+			response.ErrorResponse(c, response.ErrUnauthorized.WithMessage("token无效或已过期").Wrap(err))
+			return
+		}
+
+		c.Set(ClaimsKey, claims)
+		c.Set(RolesKey, options.roles(c, claims))
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), claimsKey{}, claims))
+		c.Next()
+	}
+}
+
+// RequireRoles 路由或路由组鉴权，需要在 Auth 之后使用，用户拥有任一角色即可访问。
+// 未认证时返回 response.ErrUnauthorized，没有角色时返回 response.ErrForbidden
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetClaims(c); !ok {
+			response.ErrorResponse(c, response.ErrUnauthorized.WithMessage("未登录或token缺失"))
+			return
+		}
+		for _, role := range GetRoles(c) {
+			for _, required := range roles {
+				if role == required {
+					c.Next()
+					return
+				}
+			}
+		}
+		response.ErrorResponse(c, response.ErrForbidden.WithDetail("require roles %v", roles))
+	}
+}
+
+// GetClaims 获取 gin.Context 中的 JWT claims
+func GetClaims(c *gin.Context) (*jwtx.Claims, bool) {
+	claims, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	v, ok := claims.(*jwtx.Claims)
+	return v, ok
+}
+
+// GetRoles 获取 gin.Context 中的用户角色
+func GetRoles(c *gin.Context) []string {
+	return c.GetStringSlice(RolesKey)
+}
+
+// ClaimsFromContext 获取请求上下文中的 JWT claims，用于service等不依赖gin的代码
+func ClaimsFromContext(ctx context.Context) (*jwtx.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*jwtx.Claims)
+	return claims, ok
+}
+
+// bearerToken 获取 Authorization 请求头中的 Bearer token
+func bearerToken(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/hchicken/pkg-go/jwtx"
+	"github.com/hchicken/pkg-go/logx"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testLogger 输出到 buf 的 JSON 日志
+func testLogger(buf *bytes.Buffer) *logx.LoggerIns {
+	l := logrus.New()
+	l.SetOutput(buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+	return &logx.LoggerIns{Logger: l}
+}
+
+// serve 执行请求并返回响应
+func serve(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestID(), AccessLog(Logger(testLogger(&buf)), SkipPaths("/ping")))
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	r.GET("/users", func(c *gin.Context) {
+		response.Success(c, RequestIDFromContext(c.Request.Context()))
+	})
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/users", nil))
+	id := w.Header().Get(response.TraceIDHeader)
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if id == "" || body["trace_id"] != id || body["data"] != id {
+		t.Errorf("expected generated request id in header, envelope and context, got %s %v", id, body)
+	}
+	if !strings.Contains(buf.String(), `"path":"/users"`) || !strings.Contains(buf.String(), `"trace_id":"`+id+`"`) {
+		t.Errorf("expected access log with trace id, got %s", buf.String())
+	}
+
+	buf.Reset()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(response.TraceIDHeader, "given")
+	if w := serve(r, req); w.Header().Get(response.TraceIDHeader) != "given" {
+		t.Errorf("expected incoming request id to be echoed, got %s", w.Header().Get(response.TraceIDHeader))
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected skipped path not to be logged, got %s", buf.String())
+	}
+}
+
+func TestRequestIDValidate(t *testing.T) {
+	type traceKey struct{}
+	r := gin.New()
+	r.Use(RequestID(RequestIDContext(func(ctx context.Context, id string) context.Context {
+		return context.WithValue(ctx, traceKey{}, id)
+	})))
+	r.GET("/trace", func(c *gin.Context) {
+		c.String(http.StatusOK, "%v", c.Request.Context().Value(traceKey{}))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	req.Header.Set(response.TraceIDHeader, "req-1.a:b_c")
+	if w := serve(r, req); w.Header().Get(response.TraceIDHeader) != "req-1.a:b_c" || w.Body.String() != "req-1.a:b_c" {
+		t.Errorf("expected valid request id to be kept and passed to context, got %s %s",
+			w.Header().Get(response.TraceIDHeader), w.Body.String())
+	}
+
+	for _, given := range []string{strings.Repeat("a", 65), "<script>", "a b"} {
+		req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set(response.TraceIDHeader, given)
+		w := serve(r, req)
+		if id := w.Header().Get(response.TraceIDHeader); id == given || id == "" || w.Body.String() != id {
+			t.Errorf("expected invalid request id %q to be regenerated, got %s %s", given, id, w.Body.String())
+		}
+	}
+}
+
+func TestRecoveryAndTimeout(t *testing.T) {
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestID(), Recovery(Logger(testLogger(&buf))))
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	r.GET("/slow", Timeout(10*time.Millisecond), func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"code":1000`) {
+		t.Errorf("expected 500 envelope, got %d %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(buf.String(), "panic recovered: boom") {
+		t.Errorf("expected panic to be logged, got %s", buf.String())
+	}
+
+	w = serve(r, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected 504, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAuth(t *testing.T) {
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected Auth to panic with the default secret")
+			}
+		}()
+		Auth()
+	}()
+	jwtx.SetSecret([]byte("test-secret"))
+
+	r := gin.New()
+	r.Use(Auth(PublicPaths("/login", "/public/*")))
+	r.GET("/login", func(c *gin.Context) { c.String(http.StatusOK, "login") })
+	r.GET("/public/docs", func(c *gin.Context) { c.String(http.StatusOK, "docs") })
+	r.GET("/me", func(c *gin.Context) {
+		claims, _ := ClaimsFromContext(c.Request.Context())
+		c.String(http.StatusOK, claims.Username)
+	})
+	admin := r.Group("/admin", RequireRoles("admin"))
+	admin.GET("/users", func(c *gin.Context) { c.String(http.StatusOK, "users") })
+
+	request := func(path string, roles ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if roles != nil {
+			token, err := jwtx.GenerateTokenWithRoles("tom", "", roles...)
+			if err != nil {
+				t.Fatalf("GenerateToken failed: %v", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return serve(r, req)
+	}
+
+	if w := request("/public/docs"); w.Code != http.StatusOK {
+		t.Errorf("expected public path to pass, got %d", w.Code)
+	}
+	if w := request("/me"); w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), `"code":1002`) {
+		t.Errorf("expected 401 envelope, got %d %s", w.Code, w.Body.String())
+	}
+	if w := request("/me", "user"); w.Code != http.StatusOK || w.Body.String() != "tom" {
+		t.Errorf("expected claims in context, got %d %s", w.Code, w.Body.String())
+	}
+	if w := request("/admin/users", "user"); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without admin role, got %d", w.Code)
+	}
+	if w := request("/admin/users", "user", "admin"); w.Code != http.StatusOK {
+		t.Errorf("expected admin to pass, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	if w := serve(r, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for invalid token, got %d", w.Code)
+	}
+}
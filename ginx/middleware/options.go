@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/jwtx"
+	"github.com/hchicken/pkg-go/logx"
+)
+
+// Option ...
+type Option func(*Options)
+
+// Options 中间件配置
+type Options struct {
+	logger       *logx.LoggerIns                                        // 日志实例
+	skipPaths    map[string]bool                                        // 不记录访问日志的路径
+	publicPaths  []string                                               // 不需要认证的路径
+	parser       func(token string) (*jwtx.Claims, error)               // token解析，默认 jwtx.ParseToken
+	roles        func(c *gin.Context, claims *jwtx.Claims) []string     // 获取用户角色
+	requestIDCtx []func(ctx context.Context, id string) context.Context // 请求ID写入请求上下文的方法
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		skipPaths: make(map[string]bool),
+		roles: func(_ *gin.Context, claims *jwtx.Claims) []string {
+			return claims.Roles
+		},
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.logger == nil {
+		opt.logger = logx.Console()
+	}
+	return opt
+}
+
+// Logger 设置输出的 logx 日志实例，默认 logx.Console()
+func Logger(ins *logx.LoggerIns) Option {
+	return func(o *Options) {
+		o.logger = ins
+	}
+}
+
+// SkipPaths 设置不记录访问日志的路径，如健康检查 /ping
+func SkipPaths(paths ...string) Option {
+	return func(o *Options) {
+		for _, path := range paths {
+			o.skipPaths[path] = true
+		}
+	}
+}
+
+// PublicPaths 设置不需要认证的路径，以 * 结尾时按前缀匹配，如 "/api/public/*"
+func PublicPaths(paths ...string) Option {
+	return func(o *Options) {
+		o.publicPaths = append(o.publicPaths, paths...)
+	}
+}
+
+// Secret 使用指定密钥校验 HS256 token，未设置时使用 jwtx.SetSecret 设置的密钥
+func Secret(secret []byte) Option {
+	return func(o *Options) {
+		o.parser = func(token string) (*jwtx.Claims, error) {
+			return jwtx.ParseTokenWithSecret(token, secret)
+		}
+	}
+}
+
+// TokenParser 设置token解析方法，默认 jwtx.ParseToken
+func TokenParser(fn func(token string) (*jwtx.Claims, error)) Option {
+	return func(o *Options) {
+		o.parser = fn
+	}
+}
+
+// RoleResolver 设置获取用户角色的方法，默认使用 token 中的 Roles，
+// 角色保存在数据库时可按 claims.Username 查询
+func RoleResolver(fn func(c *gin.Context, claims *jwtx.Claims) []string) Option {
+	return func(o *Options) {
+		o.roles = fn
+	}
+}
+
+// RequestIDContext 请求ID额外写入请求上下文的方法，如 gormx.ContextWithTraceID 使SQL日志附带请求ID
+func RequestIDContext(fn func(ctx context.Context, id string) context.Context) Option {
+	return func(o *Options) {
+		o.requestIDCtx = append(o.requestIDCtx, fn)
+	}
+}
+
+// isPublic 路径是否不需要认证
+func (o Options) isPublic(path string) bool {
+	for _, public := range o.publicPaths {
+		if strings.HasSuffix(public, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(public, "*")) {
+				return true
+			}
+		} else if path == public {
+			return true
+		}
+	}
+	return false
+}
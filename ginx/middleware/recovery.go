@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/sirupsen/logrus"
+)
+
+// Recovery panic恢复中间件，记录panic和堆栈后返回 response.ErrInternal；
+// 客户端断开连接导致的panic只记录日志
+func Recovery(opts ...Option) gin.HandlerFunc {
+	options := newOptions(opts...)
+	return func(c *gin.Context) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+
+			entry := options.logger.WithFields(logrus.Fields{
+				"method":   c.Request.Method,
+				"path":     c.Request.URL.Path,
+				"trace_id": GetRequestID(c),
+				"stack":    string(debug.Stack()),
+			})
+			if brokenPipe(err) {
+				entry.Warnf("connection broken: %v", err)
+				c.Abort()
+				return
+			}
+			entry.Errorf("panic recovered: %v", err)
+			response.JSON(c, response.Error(response.ErrInternal))
+		}()
+		c.Next()
+	}
+}
+
+// brokenPipe 是否为客户端断开连接
+func brokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+	msg := strings.ToLower(syscallErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/hchicken/pkg-go/stringx"
+)
+
+// RequestIDKey gin.Context 中请求ID的key
+const RequestIDKey = "request_id"
+
+// maxRequestIDLen 请求头中请求ID的最大长度
+const maxRequestIDLen = 64
+
+// requestIDKey 请求上下文中请求ID的key
+type requestIDKey struct{}
+
+// RequestID 请求ID中间件，请求头 private-trace-id 为空或不合法时使用 stringx.UUID 生成，
+// 写回请求头供 response.JSON 读取，同时设置响应头并保存到 gin.Context 和请求上下文，
+// 可通过 RequestIDContext 同时写入 gormx 等组件的上下文
+func RequestID(opts ...Option) gin.HandlerFunc {
+	options := newOptions(opts...)
+	return func(c *gin.Context) {
+		id := c.GetHeader(response.TraceIDHeader)
+		if !validRequestID(id) {
+			id = stringx.UUID()
+			c.Request.Header.Set(response.TraceIDHeader, id)
+		}
+		c.Header(response.TraceIDHeader, id)
+		c.Set(RequestIDKey, id)
+		ctx := context.WithValue(c.Request.Context(), requestIDKey{}, id)
+		for _, fn := range options.requestIDCtx {
+			ctx = fn(ctx, id)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID 请求ID不超过64个字符，且只包含字母、数字和 - _ . :，
+// 避免客户端传入的值污染日志和响应头
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '-', ch == '_', ch == '.', ch == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// GetRequestID 获取 gin.Context 中的请求ID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// RequestIDFromContext 获取请求上下文中的请求ID，用于service等不依赖gin的代码
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
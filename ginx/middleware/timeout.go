@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/response"
+)
+
+// Timeout 请求超时中间件，可按路由或路由组设置。为请求上下文设置超时，
+// 处理函数需要使用 c.Request.Context() 调用数据库、HTTP等下游才能提前结束；
+// 超时且尚未写入响应时返回 504 和 response.ErrTimeout
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			response.ErrorResponse(c, response.ErrTimeout.Wrap(ctx.Err()))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+module github.com/hchicken/pkg-go/ginx/paging
+
+go 1.19
+
+require (
+	github.com/gin-gonic/gin v1.8.1
+	github.com/hchicken/pkg-go/ginx v1.1.0
+	github.com/hchicken/pkg-go/gormx v1.1.0
+)
+
+require (
+	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de // indirect
+	github.com/dgrijalva/jwt-go/v4 v4.0.0-preview1 // indirect
+	github.com/gin-contrib/sse v0.1.0 // indirect
+	github.com/go-playground/locales v0.14.0 // indirect
+	github.com/go-playground/universal-translator v0.18.0 // indirect
+	github.com/go-playground/validator/v10 v10.11.1 // indirect
+	github.com/go-sql-driver/mysql v1.7.0 // indirect
+	github.com/goccy/go-json v0.9.7 // indirect
+	github.com/gofrs/uuid v4.4.0+incompatible // indirect
+	github.com/golang-sql/civil v0.0.0-20220223132316-b832511892a9 // indirect
+	github.com/golang-sql/sqlexp v0.1.0 // indirect
+	github.com/gomodule/redigo v1.8.9 // indirect
+	github.com/hchicken/pkg-go v0.0.0-20230707030714-8a20ec22d597 // indirect
+	github.com/hchicken/pkg-go/cache v1.0.0 // indirect
+	github.com/hchicken/pkg-go/logx v1.0.3 // indirect
+	github.com/hchicken/pkg-go/stringx v1.0.3 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jackc/pgx/v5 v5.3.1 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/leodido/go-urn v1.2.1 // indirect
+	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible // indirect
+	github.com/lestrrat-go/strftime v1.0.6 // indirect
+	github.com/mattn/go-isatty v0.0.14 // indirect
+	github.com/mattn/go-sqlite3 v1.14.17 // indirect
+	github.com/microsoft/go-mssqldb v0.17.0 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/pelletier/go-toml/v2 v2.0.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/sirupsen/logrus v1.9.3 // indirect
+	github.com/ugorji/go/codec v1.2.7 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	golang.org/x/crypto v0.8.0 // indirect
+	golang.org/x/net v0.9.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/text v0.9.0 // indirect
+	google.golang.org/protobuf v1.28.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gorm.io/driver/mysql v1.5.1 // indirect
+	gorm.io/driver/postgres v1.5.2 // indirect
+	gorm.io/driver/sqlite v1.5.3 // indirect
+	gorm.io/driver/sqlserver v1.4.1 // indirect
+	gorm.io/gorm v1.25.2 // indirect
+	gorm.io/plugin/dbresolver v1.5.1 // indirect
+)
+
+// 依赖的模块版本发布前指向仓库中的目录，使 GOWORK=off 时也能构建；
+// 按 README 的发布顺序打好 tag 后删除
+replace (
+	github.com/hchicken/pkg-go/cache => ../../cache
+	github.com/hchicken/pkg-go/ginx => ../../ginx
+	github.com/hchicken/pkg-go/gormx => ../../gormx
+	github.com/hchicken/pkg-go/jwtx => ../../jwtx
+	github.com/hchicken/pkg-go/logx => ../../logx
+	github.com/hchicken/pkg-go/stringx => ../../stringx
+)
@@ -0,0 +1,57 @@
+package paging
+
+// 默认分页参数
+const (
+	DefaultLimit = 20  // 默认每页数量
+	MaxLimit     = 100 // 默认每页数量上限
+)
+
+// Option ...
+type Option func(*Options)
+
+// Options 分页配置
+type Options struct {
+	defaultLimit int  // 未传 limit 时的每页数量
+	maxLimit     int  // 每页数量上限
+	cursor       bool // 是否使用游标分页
+	cursorTotal  bool // 游标分页时是否统计总数
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{defaultLimit: DefaultLimit, maxLimit: MaxLimit}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.maxLimit > 0 && opt.defaultLimit > opt.maxLimit {
+		opt.defaultLimit = opt.maxLimit
+	}
+	return opt
+}
+
+// Limit 设置未传 limit 时的每页数量，默认 20
+func Limit(n int) Option {
+	return func(o *Options) {
+		o.defaultLimit = n
+	}
+}
+
+// Max 设置每页数量上限，超过时按上限查询，默认 100
+func Max(n int) Option {
+	return func(o *Options) {
+		o.maxLimit = n
+	}
+}
+
+// Cursor 使用游标分页，忽略页码，请求带 cursor 参数时自动使用游标分页
+func Cursor(b bool) Option {
+	return func(o *Options) {
+		o.cursor = b
+	}
+}
+
+// CursorTotal 游标分页时统计总数，默认不统计，避免大表每页执行 COUNT(*)
+func CursorTotal(b bool) Option {
+	return func(o *Options) {
+		o.cursorTotal = b
+	}
+}
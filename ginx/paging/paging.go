@@ -0,0 +1,92 @@
+package paging
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/binding"
+	"github.com/hchicken/pkg-go/ginx/protos"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/hchicken/pkg-go/gormx"
+)
+
+// Normalize 规范分页参数：页码小于1时为1，limit 未传或小于1时使用默认值，超过上限时使用上限
+func Normalize(q protos.ReqQueryBase, opts ...Option) (page, limit int) {
+	return normalize(q, newOptions(opts...))
+}
+
+// normalize 按配置规范分页参数
+func normalize(q protos.ReqQueryBase, o Options) (page, limit int) {
+	page, limit = 1, o.defaultLimit
+	if q.Page != nil && *q.Page > 1 {
+		page = *q.Page
+	}
+	if q.Limit != nil && *q.Limit > 0 {
+		limit = *q.Limit
+	}
+	if o.maxLimit > 0 && limit > o.maxLimit {
+		limit = o.maxLimit
+	}
+	return page, limit
+}
+
+// Find 按查询参数分页查询 db 并返回分页数据，db 需设置 DbModel，ScanModel 和 Total 由 Find 设置。
+// s_time、e_time 同时传入时按 db 的 TimeField 过滤，sort 按字段白名单校验，
+// 游标分页默认不统计总数，Total 为0，需要时使用 CursorTotal
+func Find[T any](db *gormx.Database, q protos.ReqQueryBase, opts ...Option) (*response.Page[T], error) {
+	o := newOptions(opts...)
+	page, limit := normalize(q, o)
+
+	var (
+		items []T
+		total int64
+	)
+	db.SetScanModel(&items).SetLimit(limit).
+		SetStartTimeIf(q.STime != "", q.STime).
+		SetEndTimeIf(q.ETime != "", q.ETime)
+	if q.Sort != "" {
+		db.SetSort(q.Sort)
+	}
+
+	if o.cursor || q.Cursor != "" {
+		if o.cursorTotal {
+			db.SetTotal(&total)
+		}
+		var cursor gormx.CursorPage
+		if err := db.SetCursor(q.Cursor).SetCursorPage(&cursor).Query(); err != nil {
+			return nil, err
+		}
+		return response.NewCursorPage(items, total, limit, cursor.NextCursor), nil
+	}
+
+	if err := db.SetTotal(&total).SetPage(page).Query(); err != nil {
+		return nil, err
+	}
+	return response.NewPage(items, total, page, limit), nil
+}
+
+// Query 绑定请求中的分页参数，分页查询 db 并返回分页响应。
+// 参数错误返回 response.ErrInvalidParams，查询失败返回 response.ErrInternal，返回的错误已写入响应
+func Query[T any](c *gin.Context, db *gormx.Database, opts ...Option) error {
+	var q protos.ReqQueryBase
+	if err := binding.ShouldBindQuery(c, &q); err != nil {
+		return err
+	}
+
+	page, err := Find[T](db, q, opts...)
+	if err != nil {
+		response.ErrorResponse(c, queryError(err))
+		return err
+	}
+	response.Success(c, page)
+	return nil
+}
+
+// queryError 排序、游标、过滤字段错误为参数错误，其余为内部错误
+func queryError(err error) error {
+	if errors.Is(err, gormx.ErrInvalidSort) || errors.Is(err, gormx.ErrInvalidCursor) ||
+		errors.Is(err, gormx.ErrFieldNotAllowed) {
+		return response.ErrInvalidParams.WithMessage(err.Error()).Wrap(err)
+	}
+	return response.ErrInternal.Wrap(err)
+}
@@ -0,0 +1,109 @@
+package paging_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/paging"
+	"github.com/hchicken/pkg-go/ginx/protos"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/hchicken/pkg-go/ginx/validator"
+	"github.com/hchicken/pkg-go/gormx"
+	"github.com/hchicken/pkg-go/gormx/gormxtest"
+)
+
+type testUser struct {
+	ID   int64  `json:"id" gorm:"primaryKey"`
+	Name string `json:"name"`
+}
+
+type testResp struct {
+	Code int                     `json:"code"`
+	Data response.Page[testUser] `json:"data"`
+}
+
+// query 请求分页接口并解析响应
+func query(t *testing.T, db *gormxtest.DB, target string, opts ...paging.Option) testResp {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+	_ = paging.Query[testUser](c, gormx.NewDatabase(gormx.WithConnPool(db.GetConn()),
+		gormx.WithConnDbModel(&testUser{})), opts...)
+	var resp testResp
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return resp
+}
+
+func TestQuery(t *testing.T) {
+	validator.InitValidator()
+	db := gormxtest.New(t, gormxtest.Models(&testUser{}))
+	for i := 0; i < 25; i++ {
+		if err := db.GetConn().Create(&testUser{Name: "user"}).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	resp := query(t, db, "/users?page=3&limit=10&sort=id")
+	if page := resp.Data; len(page.Items) != 5 || page.Items[0].ID != 21 || page.Total != 25 || page.Page != 3 || page.HasMore {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	resp = query(t, db, "/users?limit=1000", paging.Max(20))
+	if page := resp.Data; len(page.Items) != 20 || page.Limit != 20 || !page.HasMore || page.Items[0].ID != 25 {
+		t.Errorf("expected capped limit, got %+v", page)
+	}
+
+	resp = query(t, db, "/users?limit=20", paging.Cursor(true))
+	if !resp.Data.HasMore || resp.Data.NextCursor == "" || resp.Data.Total != 0 {
+		t.Fatalf("expected next cursor without total, got %+v", resp.Data)
+	}
+	resp = query(t, db, "/users?limit=20&cursor="+resp.Data.NextCursor, paging.CursorTotal(true))
+	if page := resp.Data; len(page.Items) != 5 || page.HasMore || page.Items[0].ID != 5 || page.Total != 25 {
+		t.Errorf("unexpected cursor page: %+v", page)
+	}
+
+	if resp := query(t, db, "/users?sort=password"); resp.Code != response.CodeInvalidParams {
+		t.Errorf("expected invalid params for unknown sort field, got %d", resp.Code)
+	}
+}
+
+func TestFindConditions(t *testing.T) {
+	db := gormxtest.New(t, gormxtest.Models(&testUser{}))
+	for _, name := range []string{"tom", "tom", "tom", "jerry"} {
+		if err := db.GetConn().Create(&testUser{Name: name}).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// 嵌入 ReqQueryBase 的查询条件，分页参数不作为过滤字段
+	type userQuery struct {
+		protos.ReqQueryBase
+		Name string `json:"name,omitempty"`
+	}
+	limit := 2
+	q := userQuery{ReqQueryBase: protos.ReqQueryBase{Limit: &limit, Sort: "id"}, Name: "tom"}
+	find := func() *response.Page[testUser] {
+		page, err := paging.Find[testUser](gormx.NewDatabase(gormx.WithConnPool(db.GetConn()),
+			gormx.WithConnDbModel(&testUser{}), gormx.WithConnConditions(&q)), q.ReqQueryBase, paging.Cursor(true))
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		return page
+	}
+
+	page := find()
+	if len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	q.Cursor = page.NextCursor
+	if page := find(); len(page.Items) != 1 || page.Items[0].Name != "tom" || page.HasMore {
+		t.Errorf("unexpected cursor page: %+v", page)
+	}
+}
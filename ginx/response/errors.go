@@ -0,0 +1,163 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// 通用业务状态码
+const (
+	CodeInternal        = 1000 // 服务器内部错误
+	CodeInvalidParams   = 1001 // 参数错误
+	CodeUnauthorized    = 1002 // 未认证
+	CodeForbidden       = 1003 // 无权限
+	CodeNotFound        = 1004 // 资源不存在
+	CodeConflict        = 1005 // 资源冲突
+	CodeTooManyRequests = 1006 // 请求过于频繁
+	CodeTimeout         = 1007 // 请求超时
+)
+
+// 通用错误
+var (
+	ErrInternal        = Register(CodeInternal, http.StatusInternalServerError, "服务器内部错误")
+	ErrInvalidParams   = Register(CodeInvalidParams, http.StatusBadRequest, "参数错误")
+	ErrUnauthorized    = Register(CodeUnauthorized, http.StatusUnauthorized, "未登录或登录已过期")
+	ErrForbidden       = Register(CodeForbidden, http.StatusForbidden, "权限不足")
+	ErrNotFound        = Register(CodeNotFound, http.StatusNotFound, "资源不存在")
+	ErrConflict        = Register(CodeConflict, http.StatusConflict, "资源冲突")
+	ErrTooManyRequests = Register(CodeTooManyRequests, http.StatusTooManyRequests, "请求过于频繁")
+	ErrTimeout         = Register(CodeTimeout, http.StatusGatewayTimeout, "请求超时")
+)
+
+var (
+	registry   = make(map[int]*AppError)
+	registryMu sync.RWMutex
+
+	unknownMapper   = func(error) *AppError { return ErrInternal }
+	unknownMapperMu sync.RWMutex
+)
+
+// FieldError 字段错误
+type FieldError struct {
+	Field   string `json:"field"`          // 字段，使用json名称
+	Rule    string `json:"rule,omitempty"` // 校验规则
+	Message string `json:"message"`        // 错误信息
+}
+
+// AppError 应用错误，Code、Status、Message 和 Fields 返回给客户端，Detail 和 Err 只用于日志
+type AppError struct {
+	Code    int          // 业务状态码
+	Status  int          // HTTP状态码
+	Message string       // 返回给用户的消息
+	Detail  string       // 内部详情
+	Fields  []FieldError // 字段错误
+	Err     error        // 原始错误
+}
+
+// Register 注册错误码，通常在包初始化时调用，错误码重复时panic
+func Register(code, status int, message string) *AppError {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[code]; ok {
+		panic(fmt.Sprintf("response: error code %d already registered", code))
+	}
+	err := &AppError{Code: code, Status: status, Message: message}
+	registry[code] = err
+	return err
+}
+
+// SetUnknownErrorMapper 设置非 *AppError 错误的映射方法，默认按 ErrInternal 返回 500 和通用消息，
+// 需要兼容旧版本的响应时使用 SetUnknownErrorMapper(LegacyError)
+func SetUnknownErrorMapper(fn func(err error) *AppError) {
+	unknownMapperMu.Lock()
+	defer unknownMapperMu.Unlock()
+	unknownMapper = fn
+}
+
+// LegacyError 旧版本的错误映射：HTTP 400、业务状态码 CodeError，消息为 err.Error()
+func LegacyError(err error) *AppError {
+	return &AppError{Code: CodeError, Status: http.StatusBadRequest, Message: err.Error()}
+}
+
+// mapUnknown 映射非 *AppError 错误，映射方法返回nil时使用 ErrInternal
+func mapUnknown(err error) *AppError {
+	unknownMapperMu.RLock()
+	mapper := unknownMapper
+	unknownMapperMu.RUnlock()
+	if appErr := mapper(err); appErr != nil {
+		return appErr
+	}
+	return ErrInternal
+}
+
+// Lookup 根据错误码获取注册的错误
+func Lookup(code int) (*AppError, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	err, ok := registry[code]
+	return err, ok
+}
+
+// Error 实现 error，包含内部详情和原始错误
+func (e *AppError) Error() string {
+	msg := fmt.Sprintf("[%d] %s", e.Code, e.Message)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap 返回原始错误
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is 错误码相同时认为是同一错误，支持 errors.Is(err, response.ErrNotFound)
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// clone 复制错误，注册的错误不会被修改
+func (e *AppError) clone() *AppError {
+	err := *e
+	err.Fields = append([]FieldError(nil), e.Fields...)
+	return &err
+}
+
+// WithMessage 返回替换用户消息的副本
+func (e *AppError) WithMessage(message string) *AppError {
+	err := e.clone()
+	err.Message = message
+	return err
+}
+
+// WithDetail 返回附带内部详情的副本
+func (e *AppError) WithDetail(format string, args ...interface{}) *AppError {
+	err := e.clone()
+	err.Detail = fmt.Sprintf(format, args...)
+	return err
+}
+
+// Wrap 返回包装原始错误的副本
+func (e *AppError) Wrap(cause error) *AppError {
+	err := e.clone()
+	err.Err = cause
+	return err
+}
+
+// WithField 返回附带字段错误的副本
+func (e *AppError) WithField(field, message string) *AppError {
+	return e.WithFields(FieldError{Field: field, Message: message})
+}
+
+// WithFields 返回附带多个字段错误的副本
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	err := e.clone()
+	err.Fields = append(err.Fields, fields...)
+	return err
+}
@@ -0,0 +1,88 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errOrderClosed = Register(20001, http.StatusConflict, "订单已关闭")
+
+func TestErrorResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders/1/pay", nil)
+
+	cause := errors.New("sql: no rows in result set")
+	err := fmt.Errorf("pay order: %w", errOrderClosed.WithDetail("order %d", 1).Wrap(cause).WithField("order_id", "已关闭"))
+	ErrorResponse(c, err)
+
+	var body struct {
+		Code    int          `json:"code"`
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if w.Code != http.StatusConflict || body.Code != 20001 || body.Message != "订单已关闭" || len(body.Errors) != 1 || body.Errors[0] != (FieldError{Field: "order_id", Message: "已关闭"}) {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "sql") || !strings.Contains(c.Errors.String(), "sql: no rows") {
+		t.Errorf("expected internal detail only in c.Errors, got %s", w.Body.String())
+	}
+
+	if !errors.Is(err, errOrderClosed) || !errors.Is(err, cause) || errors.Is(err, ErrNotFound) {
+		t.Error("expected errors.Is to match by code and unwrap the cause")
+	}
+	if errOrderClosed.Fields != nil || errOrderClosed.Detail != "" {
+		t.Error("expected registered error to stay unchanged")
+	}
+	if registered, ok := Lookup(20001); !ok || registered != errOrderClosed {
+		t.Error("expected code to be registered")
+	}
+}
+
+func TestErrorResponseUnknown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/orders", nil)
+
+	ErrorResponse(c, errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if w.Code != http.StatusInternalServerError || body.Code != CodeInternal || body.Message != ErrInternal.Message {
+		t.Errorf("expected unknown error to be mapped to ErrInternal, got %d %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "10.0.0.1") || !strings.Contains(c.Errors.String(), "connection refused") {
+		t.Errorf("expected raw error only in c.Errors, got %s", w.Body.String())
+	}
+
+	// 兼容旧版本的映射
+	SetUnknownErrorMapper(LegacyError)
+	defer SetUnknownErrorMapper(func(error) *AppError { return ErrInternal })
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/orders", nil)
+	ErrorResponse(c, errors.New("name is required"))
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || body.Code != CodeError || body.Message != "name is required" {
+		t.Errorf("expected legacy mapping, got %d %s", w.Code, w.Body.String())
+	}
+}
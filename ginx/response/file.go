@@ -0,0 +1,162 @@
+package response
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileStream 设置文件流，支持 Range 请求，大文件无需读入内存
+func FileStream(r io.ReadSeeker) Option {
+	return func(o *Options) {
+		o.fileStream = r
+	}
+}
+
+// FilePath 设置本地文件路径，未设置 FileName 时使用文件名，
+// 同时使用文件修改时间作为 Last-Modified 并生成 ETag
+func FilePath(name string) Option {
+	return func(o *Options) {
+		o.filePath = name
+	}
+}
+
+// ContentType 设置文件类型，默认按文件名后缀或文件内容识别
+func ContentType(contentType string) Option {
+	return func(o *Options) {
+		o.contentType = contentType
+	}
+}
+
+// Inline 浏览器内直接打开文件，默认作为附件下载
+func Inline(inline bool) Option {
+	return func(o *Options) {
+		o.inline = inline
+	}
+}
+
+// ModTime 设置文件修改时间，用于 Last-Modified 和 If-Modified-Since 条件请求
+func ModTime(t time.Time) Option {
+	return func(o *Options) {
+		o.modTime = t
+	}
+}
+
+// ETag 设置文件的ETag，用于 If-None-Match、If-Range 条件请求，
+// FileBytes 和 FilePath 未设置时自动生成
+func ETag(tag string) Option {
+	return func(o *Options) {
+		o.etag = tag
+	}
+}
+
+// serveFile 返回文件，Range 和条件请求由 http.ServeContent 处理
+func serveFile(c *gin.Context, options *Options) {
+	content := options.fileStream
+	switch {
+	case options.filePath != "":
+		f, err := os.Open(options.filePath)
+		if err != nil {
+			fileError(c, err)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			fileError(c, err)
+			return
+		}
+		if info.IsDir() {
+			ErrorResponse(c, ErrNotFound.WithDetail("%s is a directory", options.filePath))
+			return
+		}
+		if options.filename == DefaultFileName {
+			options.filename = filepath.Base(options.filePath)
+		}
+		if options.modTime.IsZero() {
+			options.modTime = info.ModTime()
+		}
+		if options.etag == "" {
+			options.etag = fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+		}
+		content = f
+	case len(options.fileBytes) > 0:
+		if options.etag == "" {
+			options.etag = fmt.Sprintf(`"%x"`, md5.Sum(options.fileBytes))
+		}
+		content = bytes.NewReader(options.fileBytes)
+	}
+	if content == nil {
+		ErrorResponse(c, ErrInvalidParams.WithMessage("文件数据为空"))
+		return
+	}
+
+	filename := path.Base(options.filename)
+	if filename == "" || filename == "." || filename == "/" {
+		filename = DefaultFileName
+	}
+	c.Header("Content-Disposition", contentDisposition(filename, options.inline))
+	if options.contentType != "" {
+		c.Header("Content-Type", options.contentType)
+	}
+	if options.etag != "" {
+		c.Header("ETag", options.etag)
+	}
+	http.ServeContent(c.Writer, c.Request, filename, options.modTime, content)
+	c.Writer.WriteHeaderNow() // 304 等无响应体的状态码
+}
+
+// fileError 打开文件失败，文件不存在时返回 ErrNotFound
+func fileError(c *gin.Context, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		ErrorResponse(c, ErrNotFound.Wrap(err))
+		return
+	}
+	ErrorResponse(c, ErrInternal.Wrap(err))
+}
+
+// contentDisposition 生成 Content-Disposition，filename 为ASCII兼容名称，
+// filename* 为 RFC 5987 编码的 UTF-8 文件名
+func contentDisposition(filename string, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, asciiFilename(filename), encodeRFC5987(filename))
+}
+
+// asciiFilename 非ASCII字符、引号和反斜杠替换为下划线
+func asciiFilename(filename string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+}
+
+// encodeRFC5987 按 RFC 5987 的 attr-char 编码，其余字节转为 %XX
+func encodeRFC5987(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' ||
+			strings.IndexByte("!#$&+-.^_`|~", ch) >= 0 {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
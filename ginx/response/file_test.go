@@ -0,0 +1,70 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveTestFile 请求文件并返回响应
+func serveTestFile(header http.Header, opts ...Option) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/download", nil)
+	for k, v := range header {
+		c.Request.Header[k] = v
+	}
+	File(c, opts...)
+	return w
+}
+
+func TestFilePath(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "报告.txt")
+	if err := os.WriteFile(name, []byte("0123456789"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	w := serveTestFile(http.Header{"Range": {"bytes=2-5"}}, FilePath(name))
+	if w.Code != http.StatusPartialContent || w.Body.String() != "2345" || w.Header().Get("Content-Range") != "bytes 2-5/10" {
+		t.Errorf("unexpected range response: %d %q %v", w.Code, w.Body.String(), w.Header())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain, got %s", ct)
+	}
+	want := `attachment; filename="__.txt"; filename*=UTF-8''%E6%8A%A5%E5%91%8A.txt`
+	if cd := w.Header().Get("Content-Disposition"); cd != want {
+		t.Errorf("expected %s, got %s", want, cd)
+	}
+
+	etag := w.Header().Get("ETag")
+	if w := serveTestFile(http.Header{"If-None-Match": {etag}}, FilePath(name)); w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching ETag, got %d", w.Code)
+	}
+	if w := serveTestFile(nil, FilePath(filepath.Join(t.TempDir(), "missing.txt"))); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for missing file, got %d", w.Code)
+	}
+}
+
+func TestFileStream(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 16)
+	w := serveTestFile(nil, FileStream(strings.NewReader(png)), Inline(true))
+	if w.Code != http.StatusOK || w.Body.String() != png {
+		t.Fatalf("unexpected response: %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected sniffed image/png, got %s", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "inline;") {
+		t.Errorf("expected inline disposition, got %s", cd)
+	}
+
+	if w := serveTestFile(nil, FileBytes(nil)); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for empty file, got %d", w.Code)
+	}
+}
@@ -0,0 +1,39 @@
+package response
+
+// Page 分页响应数据，页码分页时根据总数计算 HasMore，游标分页时 Page 为0并返回 NextCursor
+type Page[T any] struct {
+	Items      []T    `json:"items"`                 // 当前页数据
+	Total      int64  `json:"total"`                 // 总数，游标分页默认不统计
+	Page       int    `json:"page,omitempty"`        // 页码
+	Limit      int    `json:"limit"`                 // 每页数量
+	HasMore    bool   `json:"has_more"`              // 是否还有更多数据
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页游标
+}
+
+// NewPage 创建页码分页响应数据，items 为nil时返回空数组
+func NewPage[T any](items []T, total int64, page, limit int) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		HasMore: int64(page)*int64(limit) < total,
+	}
+}
+
+// NewCursorPage 创建游标分页响应数据，items 为nil时返回空数组
+func NewCursorPage[T any](items []T, total int64, limit int, nextCursor string) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items:      items,
+		Total:      total,
+		Limit:      limit,
+		HasMore:    nextCursor != "",
+		NextCursor: nextCursor,
+	}
+}
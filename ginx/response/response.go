@@ -1,10 +1,10 @@
 package response
 
 import (
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
-	"net/url"
-	"path"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -36,7 +36,14 @@ type Options struct {
 	async       bool                   // 是否异步处理
 	customField map[string]interface{} // 自定义字段
 	fileBytes   []byte                 // 文件字节数据
+	fileStream  io.ReadSeeker          // 文件流
+	filePath    string                 // 本地文件路径
 	filename    string                 // 文件名
+	contentType string                 // 文件类型
+	inline      bool                   // 是否在浏览器内打开
+	modTime     time.Time              // 文件修改时间
+	etag        string                 // 文件ETag
+	err         error                  // 原始错误，只记录到 c.Errors
 }
 
 // newDefaultOptions 创建默认配置
@@ -60,13 +67,23 @@ func Message(msg string) Option {
 	}
 }
 
-// Error 设置错误响应
+// Error 设置错误响应，错误链中有 *AppError 时使用其业务状态码、HTTP状态码、用户消息和字段错误，
+// 不返回内部详情；其他错误默认按 ErrInternal 返回(可通过 SetUnknownErrorMapper 修改)，原始错误只记录到 c.Errors
 func Error(err error) Option {
 	return func(o *Options) {
-		if err != nil {
-			o.message = err.Error()
-			o.status = CodeError
-			o.code = http.StatusBadRequest
+		if err == nil {
+			return
+		}
+		o.err = err
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			appErr = mapUnknown(err)
+		}
+		o.message = appErr.Message
+		o.status = appErr.Code
+		o.code = appErr.Status
+		if len(appErr.Fields) > 0 {
+			o.customField["errors"] = appErr.Fields
 		}
 	}
 }
@@ -149,11 +166,15 @@ func JSON(c *gin.Context, opts ...Option) {
 	for key, value := range options.customField {
 		response[key] = value
 	}
+	if options.err != nil {
+		_ = c.Error(options.err)
+	}
 
 	c.AbortWithStatusJSON(options.code, response)
 }
 
-// File 返回文件下载响应
+// File 返回文件响应，文件来源为 FilePath、FileStream 或 FileBytes，
+// 支持 Range 断点续传、ETag/Last-Modified 条件请求，Content-Disposition 使用 RFC 5987 编码文件名
 func File(c *gin.Context, opts ...Option) {
 	options := newDefaultOptions()
 
@@ -162,21 +183,7 @@ func File(c *gin.Context, opts ...Option) {
 		opt(options)
 	}
 
-	if len(options.fileBytes) == 0 {
-		JSON(c, Error(fmt.Errorf("文件数据为空")))
-		return
-	}
-
-	// 设置文件下载头
-	filename := options.filename
-	if filename == "" {
-		filename = DefaultFileName
-	}
-
-	disposition := fmt.Sprintf(`attachment; filename=%s`, url.PathEscape(path.Base(filename)))
-	c.Header("Content-Disposition", disposition)
-	c.Header("Content-Type", "application/octet-stream")
-	c.Data(http.StatusOK, "application/octet-stream", options.fileBytes)
+	serveFile(c, options)
 }
 
 // Json 向后兼容的JSON响应方法
@@ -209,11 +216,12 @@ func Fail(c *gin.Context, message string, httpStatus ...int) {
 	JSON(c, Message(message), StatusInt(CodeError), Code(status))
 }
 
-// ErrorResponse 错误响应
+// ErrorResponse 错误响应，*AppError 按 Error 映射，httpStatus 可覆盖HTTP状态码。
+// 原始错误记录到 c.Errors，可通过访问日志查看内部详情
 func ErrorResponse(c *gin.Context, err error, httpStatus ...int) {
-	status := http.StatusBadRequest
+	opts := []Option{Error(err)}
 	if len(httpStatus) > 0 {
-		status = httpStatus[0]
+		opts = append(opts, Code(httpStatus[0]))
 	}
-	JSON(c, Error(err), Code(status))
+	JSON(c, opts...)
 }
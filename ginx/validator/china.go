@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	mobilePattern = regexp.MustCompile(`^(?:\+?86)?1[3-9]\d{9}$`)
+	idCardPattern = regexp.MustCompile(`^\d{17}[\dXx]$`)
+	usccPattern   = regexp.MustCompile(`^[0-9A-HJ-NPQRTUWXY]{2}\d{6}[0-9A-HJ-NPQRTUWXY]{10}$`)
+)
+
+// idCardWeights 身份证前17位的加权因子
+var idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// idCardCheckCodes 身份证校验码，按加权和除以11的余数取值
+const idCardCheckCodes = "10X98765432"
+
+// usccWeights 统一社会信用代码前17位的加权因子
+var usccWeights = []int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// usccChars 统一社会信用代码字符集，字符的值为其下标
+const usccChars = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+
+// mobile 中国大陆手机号码，允许 86 或 +86 前缀
+func mobile(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	return v == "" || mobilePattern.MatchString(v)
+}
+
+// idCard 18位居民身份证号码，校验出生日期和校验码
+func idCard(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	if v == "" {
+		return true
+	}
+	if !idCardPattern.MatchString(v) {
+		return false
+	}
+	if _, err := time.Parse("20060102", v[6:14]); err != nil {
+		return false
+	}
+
+	sum := 0
+	for i, w := range idCardWeights {
+		sum += int(v[i]-'0') * w
+	}
+	return idCardCheckCodes[sum%11] == strings.ToUpper(v[17:])[0]
+}
+
+// uscc 18位统一社会信用代码，校验校验码
+func uscc(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	if v == "" {
+		return true
+	}
+	if !usccPattern.MatchString(v) {
+		return false
+	}
+
+	sum := 0
+	for i, w := range usccWeights {
+		sum += strings.IndexByte(usccChars, v[i]) * w
+	}
+	return usccChars[(31-sum%31)%31] == v[17]
+}
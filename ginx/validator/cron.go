@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// cronField cron表达式字段的取值范围，any 为 true 时允许 ?
+type cronField struct {
+	min, max int
+	names    map[string]int
+	any      bool
+}
+
+var (
+	cronSecond  = cronField{min: 0, max: 59}
+	cronMinute  = cronField{min: 0, max: 59}
+	cronHour    = cronField{min: 0, max: 23}
+	cronDay     = cronField{min: 1, max: 31, any: true}
+	cronMonth   = cronField{min: 1, max: 12, names: map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}}
+	cronWeekday = cronField{min: 0, max: 7, names: map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}, any: true}
+)
+
+// cronDescriptors cron预定义表达式
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// cron cron表达式：5个字段(分 时 日 月 周)，或6个字段(首位为秒)，
+// 支持 * , - / ?、月份和星期的英文缩写、@daily 等预定义表达式及 @every 1h30m
+func cron(fl validator.FieldLevel) bool {
+	v := strings.TrimSpace(fl.Field().String())
+	if v == "" {
+		return true
+	}
+	if strings.HasPrefix(v, "@") {
+		if strings.HasPrefix(v, "@every ") {
+			d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(v, "@every ")))
+			return err == nil && d > 0
+		}
+		return cronDescriptors[v]
+	}
+
+	fields := strings.Fields(v)
+	var spec []cronField
+	switch len(fields) {
+	case 5:
+		spec = []cronField{cronMinute, cronHour, cronDay, cronMonth, cronWeekday}
+	case 6:
+		spec = []cronField{cronSecond, cronMinute, cronHour, cronDay, cronMonth, cronWeekday}
+	default:
+		return false
+	}
+	for i, field := range fields {
+		if !spec[i].valid(field) {
+			return false
+		}
+	}
+	return true
+}
+
+// valid 校验字段，多个取值用逗号分隔
+func (f cronField) valid(field string) bool {
+	if field == "?" {
+		return f.any
+	}
+	for _, item := range strings.Split(field, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return false
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+		low, high, isRange := strings.Cut(rng, "-")
+		start, ok := f.value(low)
+		if !ok {
+			return false
+		}
+		if isRange {
+			end, ok := f.value(high)
+			if !ok || start > end {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// value 解析单个取值，支持数字和英文缩写
+func (f cronField) value(s string) (int, bool) {
+	if n, ok := f.names[strings.ToUpper(s)]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, false
+	}
+	return n, true
+}
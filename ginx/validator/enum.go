@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	enumsMu sync.RWMutex
+	// enums 已注册的枚举，名称 -> 取值
+	enums = map[string][]string{}
+)
+
+// RegisterEnum 注册枚举取值，配合 enum=<name> 规则使用，
+// 如 RegisterEnum("status", "enabled", "disabled") 后使用 binding:"enum=status"
+func RegisterEnum(name string, values ...interface{}) {
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		items = append(items, fmt.Sprint(v))
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	enums[name] = items
+}
+
+// enum 取值必须在注册的枚举中，支持字符串和数字，枚举未注册时校验不通过
+func enum(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	var v string
+	switch field.Kind() {
+	case reflect.String:
+		if v = field.String(); v == "" {
+			return true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		v = fmt.Sprint(field.Interface())
+	default:
+		return false
+	}
+
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+	for _, item := range enums[fl.Param()] {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// enumValues 错误信息中的枚举取值
+func enumValues(fe validator.FieldError) string {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+	return strings.Join(enums[fe.Param()], " ")
+}
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"bytes"
+	"net"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ipRange IP地址、CIDR 或 IP范围，如 10.0.0.1、10.0.0.0/24、10.0.0.1-10.0.0.20，
+// 范围的起止地址必须是同一协议且起始地址不大于结束地址
+func ipRange(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	if v == "" {
+		return true
+	}
+	if strings.Contains(v, "/") {
+		_, _, err := net.ParseCIDR(v)
+		return err == nil
+	}
+
+	start, end, ok := strings.Cut(v, "-")
+	if !ok {
+		return net.ParseIP(v) != nil
+	}
+	startIP, endIP := net.ParseIP(strings.TrimSpace(start)), net.ParseIP(strings.TrimSpace(end))
+	if startIP == nil || endIP == nil {
+		return false
+	}
+	if (startIP.To4() == nil) != (endIP.To4() == nil) {
+		return false
+	}
+	return bytes.Compare(startIP.To16(), endIP.To16()) <= 0
+}
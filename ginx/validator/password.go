@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// defaultPasswordMinLen 强密码默认最小长度
+const defaultPasswordMinLen = 8
+
+// strongPassword 强密码：长度不少于参数指定的位数(默认8位)，且包含大写字母、小写字母、数字和特殊字符，
+// 如 strongPassword 或 strongPassword=12。参数不是正整数时记录一次日志并使用默认长度，可在启动时用 CheckStruct 检查
+func strongPassword(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	if v == "" {
+		return true
+	}
+	minLen := defaultPasswordMinLen
+	if p := fl.Param(); p != "" {
+		if n, err := passwordParam(p); err != nil {
+			warnParam("strongPassword", err)
+		} else {
+			minLen = n
+		}
+	}
+	if utf8.RuneCountInString(v) < minLen {
+		return false
+	}
+
+	var upper, lower, digit, special bool
+	for _, r := range v {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsSpace(r):
+			return false
+		default:
+			special = true
+		}
+	}
+	return upper && lower && digit && special
+}
+
+// passwordParam 解析最小长度参数，必须是正整数
+func passwordParam(param string) (int, error) {
+	n, err := strconv.Atoi(param)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid strongPassword param %q: must be a positive integer", param)
+	}
+	return n, nil
+}
+
+// checkPassword 检查 strongPassword 的参数
+func checkPassword(param string, _ reflect.Type) error {
+	if param == "" {
+		return nil
+	}
+	_, err := passwordParam(param)
+	return err
+}
+
+// passwordMinLen 错误信息中的最小长度
+func passwordMinLen(fe validator.FieldError) string {
+	if fe.Param() != "" {
+		return fe.Param()
+	}
+	return strconv.Itoa(defaultPasswordMinLen)
+}
@@ -0,0 +1,204 @@
+package validator
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// rule 自定义校验规则
+type rule struct {
+	fn       validator.Func
+	messages map[string]string                             // 语言 -> 错误信息，{0} 为字段名，{1} 为规则参数
+	param    func(fe validator.FieldError) string          // 错误信息中 {1} 的取值，默认为规则参数
+	check    func(param string, parent reflect.Type) error // 检查规则参数，parent 为字段所在的结构体
+}
+
+var (
+	rulesMu sync.Mutex
+	// rules 已注册的自定义规则
+	rules = map[string]*rule{
+		"timeUnix": {fn: timeStringToUnix, messages: map[string]string{
+			LangZH: "{0}必须是有效的时间",
+			LangEN: "{0} must be a valid time",
+		}},
+		"trim": {fn: doTrimStringField, messages: map[string]string{
+			LangZH: "{0}格式不正确",
+			LangEN: "{0} is invalid",
+		}},
+		"timeString": {fn: validTimeString, messages: map[string]string{
+			LangZH: "{0}必须是 2006-01-02 15:04:05 格式的时间",
+			LangEN: "{0} must be a time in the format 2006-01-02 15:04:05",
+		}},
+		"notBlank": {fn: notBlank, messages: map[string]string{
+			LangZH: "{0}不能为空",
+			LangEN: "{0} cannot be blank",
+		}},
+		"mobile": {fn: mobile, messages: map[string]string{
+			LangZH: "{0}必须是有效的手机号码",
+			LangEN: "{0} must be a valid mobile number",
+		}},
+		"idCard": {fn: idCard, messages: map[string]string{
+			LangZH: "{0}必须是有效的身份证号码",
+			LangEN: "{0} must be a valid resident ID card number",
+		}},
+		"uscc": {fn: uscc, messages: map[string]string{
+			LangZH: "{0}必须是有效的统一社会信用代码",
+			LangEN: "{0} must be a valid unified social credit code",
+		}},
+		"strongPassword": {fn: strongPassword, param: passwordMinLen, check: checkPassword, messages: map[string]string{
+			LangZH: "{0}长度不能少于{1}位，且必须包含大写字母、小写字母、数字和特殊字符",
+			LangEN: "{0} must be at least {1} characters and contain upper and lower case letters, digits and special characters",
+		}},
+		"ipRange": {fn: ipRange, messages: map[string]string{
+			LangZH: "{0}必须是有效的IP地址、CIDR或IP范围",
+			LangEN: "{0} must be a valid IP address, CIDR or IP range",
+		}},
+		"cron": {fn: cron, messages: map[string]string{
+			LangZH: "{0}必须是有效的cron表达式",
+			LangEN: "{0} must be a valid cron expression",
+		}},
+		"enum": {fn: enum, param: enumValues, messages: map[string]string{
+			LangZH: "{0}必须是[{1}]中的一个",
+			LangEN: "{0} must be one of [{1}]",
+		}},
+		"timeBefore": {fn: timeBefore, check: checkTimeBefore, messages: map[string]string{
+			LangZH: "{0}不能晚于{1}",
+			LangEN: "{0} must not be later than {1}",
+		}},
+	}
+)
+
+// RegisterRule 注册自定义规则及其各语言的错误信息，{0} 为字段名，{1} 为规则参数，
+// 如 RegisterRule("even", fn, map[string]string{"zh": "{0}必须是偶数", "en": "{0} must be even"})。
+// InitValidator 之前注册的规则在初始化时生效，之后注册的立即生效
+func RegisterRule(tag string, fn validator.Func, messages map[string]string) error {
+	if tag == "" || fn == nil {
+		return fmt.Errorf("rule tag and func are required")
+	}
+	r := &rule{fn: fn, messages: messages}
+
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[tag] = r
+	if v, ok := engine(); ok && uni != nil {
+		return registerRule(v, tag, r)
+	}
+	return nil
+}
+
+// engine gin 使用的校验器
+func engine() (*validator.Validate, bool) {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	return v, ok
+}
+
+// registerRules 注册全部自定义规则及翻译
+func registerRules(v *validator.Validate) error {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	for tag, r := range rules {
+		if err := registerRule(v, tag, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerRule 注册规则及翻译，需先初始化 uni
+func registerRule(v *validator.Validate, tag string, r *rule) error {
+	if err := v.RegisterValidation(tag, r.fn); err != nil {
+		return err
+	}
+	for lang, text := range r.messages {
+		trans, found := uni.GetTranslator(lang)
+		if !found {
+			continue
+		}
+		text := text
+		err := v.RegisterTranslation(tag, trans, func(t ut.Translator) error {
+			return t.Add(tag, text, true)
+		}, func(t ut.Translator, fe validator.FieldError) string {
+			param := fe.Param()
+			if r.param != nil {
+				param = r.param(fe)
+			}
+			msg, err := t.T(fe.Tag(), fe.Field(), param)
+			if err != nil {
+				return fe.Error()
+			}
+			return msg
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warned 已记录过的参数错误
+var warned sync.Map
+
+// warnParam 每个参数错误只记录一次日志
+func warnParam(tag string, err error) {
+	if _, loaded := warned.LoadOrStore(tag+":"+err.Error(), true); !loaded {
+		log.Printf("validator: %s: %v", tag, err)
+	}
+}
+
+// CheckStruct 检查结构体(含嵌套结构体)binding 标签中自定义规则的参数，
+// 如 timeBefore 指向的字段是否存在、strongPassword 的长度是否为正整数，建议在启动时调用，
+// 如 validator.CheckStruct(&CreateUserReq{}, &ListUserReq{})
+func CheckStruct(values ...interface{}) error {
+	visited := make(map[reflect.Type]bool)
+	for _, v := range values {
+		if err := checkType(reflect.TypeOf(v), visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkType 检查类型中各字段的规则参数
+func checkType(typ reflect.Type, visited map[reflect.Type]bool) error {
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice ||
+		typ.Kind() == reflect.Array || typ.Kind() == reflect.Map) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct || visited[typ] {
+		return nil
+	}
+	visited[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if err := checkTag(typ, field); err != nil {
+			return err
+		}
+		if err := checkType(field.Type, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTag 检查字段 binding 标签中的规则参数
+func checkTag(parent reflect.Type, field reflect.StructField) error {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	for _, item := range strings.FieldsFunc(field.Tag.Get("binding"), func(r rune) bool { return r == ',' || r == '|' }) {
+		tag, param, _ := strings.Cut(item, "=")
+		if r, ok := rules[tag]; ok && r.check != nil {
+			if err := r.check(param, parent); err != nil {
+				return fmt.Errorf("%s.%s: %w", parent.Name(), field.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	playground "github.com/go-playground/validator/v10"
+	"github.com/hchicken/pkg-go/ginx/protos"
+	"github.com/hchicken/pkg-go/ginx/validator"
+)
+
+func TestRules(t *testing.T) {
+	validator.InitValidator()
+	validator.RegisterEnum("status", "enabled", "disabled")
+	v := binding.Validator.Engine().(*playground.Validate)
+
+	cases := []struct {
+		tag   string
+		value interface{}
+		valid bool
+	}{
+		{"mobile", "13800138000", true},
+		{"mobile", "+8613800138000", true},
+		{"mobile", "12800138000", false},
+		{"idCard", "11010519491231002X", true},
+		{"idCard", "110105194912310021", false},
+		{"idCard", "110105194913310028", false},
+		{"uscc", "91350100M000100Y43", true},
+		{"uscc", "91350100M000100Y44", false},
+		{"strongPassword", "Abcdef1!", true},
+		{"strongPassword", "abcdef1!", false},
+		{"strongPassword=10", "Abcdef1!", false},
+		{"ipRange", "10.0.0.0/24", true},
+		{"ipRange", "10.0.0.1-10.0.0.20", true},
+		{"ipRange", "10.0.0.20-10.0.0.1", false},
+		{"ipRange", "10.0.0.1-::1", false},
+		{"cron", "*/5 9-18 * * MON-FRI", true},
+		{"cron", "0 0 12 ? * *", true},
+		{"cron", "@every 1h30m", true},
+		{"cron", "60 * * * *", false},
+		{"cron", "* * * *", false},
+		{"enum=status", "enabled", true},
+		{"enum=status", "deleted", false},
+		{"enum=unknown", "enabled", false},
+	}
+	for _, c := range cases {
+		if err := v.Var(c.value, c.tag); (err == nil) != c.valid {
+			t.Errorf("%s(%v): expected valid=%v, got %v", c.tag, c.value, c.valid, err)
+		}
+	}
+}
+
+func TestTimeBefore(t *testing.T) {
+	validator.InitValidator()
+
+	req := protos.ReqQueryBase{STime: "2024-01-02 00:00:00", ETime: "2024-01-01 00:00:00"}
+	err := binding.Validator.ValidateStruct(&req)
+	fields := validator.Translate(err, validator.LangZH)
+	if len(fields) != 1 || fields[0].Field != "s_time" || fields[0].Message != "s_time不能晚于e_time" {
+		t.Errorf("expected s_time error, got %+v", fields)
+	}
+
+	req.ETime = "2024-01-03 00:00:00"
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		t.Errorf("expected valid time range, got %v", err)
+	}
+}
+
+func TestCheckStruct(t *testing.T) {
+	validator.InitValidator()
+	type badTime struct {
+		STime string `json:"s_time" binding:"timeBefore=end_time"`
+		ETime string `json:"e_time"`
+	}
+	type badPassword struct {
+		Password string `json:"password" binding:"omitempty,strongPassword=abc"`
+	}
+	type nested struct {
+		Query protos.ReqQueryBase
+		Items []*badPassword
+	}
+
+	if err := validator.CheckStruct(&protos.ReqQueryBase{}); err != nil {
+		t.Errorf("expected valid struct, got %v", err)
+	}
+	for name, v := range map[string]interface{}{"timeBefore": &badTime{}, "strongPassword": &nested{}} {
+		if err := validator.CheckStruct(v); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: expected invalid param error, got %v", name, err)
+		}
+	}
+
+	// 参数错误时校验不 panic，strongPassword 使用默认长度，timeBefore 跳过校验
+	if err := binding.Validator.ValidateStruct(&badPassword{Password: "Abcdef1!"}); err != nil {
+		t.Errorf("expected default min length, got %v", err)
+	}
+	if err := binding.Validator.ValidateStruct(&badTime{STime: "2024-01-02 00:00:00"}); err != nil {
+		t.Errorf("expected timeBefore to be skipped, got %v", err)
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	validator.InitValidator()
+	err := validator.RegisterRule("even", func(fl playground.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}, map[string]string{validator.LangZH: "{0}必须是偶数", validator.LangEN: "{0} must be even"})
+	if err != nil {
+		t.Fatalf("RegisterRule failed: %v", err)
+	}
+
+	req := struct {
+		Count int `json:"count" binding:"even"`
+	}{Count: 3}
+	fields := validator.Translate(binding.Validator.ValidateStruct(&req), validator.LangEN)
+	if len(fields) != 1 || fields[0].Message != "count must be even" {
+		t.Errorf("expected translated custom rule error, got %+v", fields)
+	}
+}
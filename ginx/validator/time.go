@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"fmt"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -37,3 +39,82 @@ func validTimeString(fl validator.FieldLevel) bool {
 	}
 	return true
 }
+
+// timeBefore 时间不能晚于同级的另一个字段，参数为该字段的json、form名称或字段名，
+// 如 binding:"timeBefore=e_time"。任一时间为空时不校验，参数指定的字段不存在时记录一次日志并跳过校验，
+// 可在启动时用 CheckStruct 检查
+func timeBefore(fl validator.FieldLevel) bool {
+	other, ok := siblingField(fl.Parent(), fl.Param())
+	if !ok {
+		warnParam("timeBefore", fmt.Errorf("field %q not found", fl.Param()))
+		return true
+	}
+	start, ok := timeValue(fl.Field())
+	if !ok {
+		return true
+	}
+	end, ok := timeValue(other)
+	if !ok {
+		return true
+	}
+	return !start.After(end)
+}
+
+// timeValue 获取字段的时间，支持时间字符串和 time.Time，为空或无法解析时返回false
+func timeValue(v reflect.Value) (time.Time, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return time.Time{}, false
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.CanInterface() {
+		return time.Time{}, false
+	}
+	switch t := v.Interface().(type) {
+	case string:
+		if t == "" {
+			return time.Time{}, false
+		}
+		unix, err := date.TimeToUnixV2(t)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(unix, 0), true
+	case time.Time:
+		return t, !t.IsZero()
+	}
+	return time.Time{}, false
+}
+
+// siblingField 按json、form名称或字段名查找结构体中的字段
+func siblingField(parent reflect.Value, name string) (reflect.Value, bool) {
+	for parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	if i := fieldIndex(parent.Type(), name); i >= 0 {
+		return parent.Field(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// fieldIndex 按json、form名称或字段名查找结构体字段的下标，不存在时返回-1
+func fieldIndex(typ reflect.Type, name string) int {
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Name == name || fieldName(field) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkTimeBefore 检查 timeBefore 的参数是否为同级字段
+func checkTimeBefore(param string, parent reflect.Type) error {
+	if param == "" || fieldIndex(parent, param) < 0 {
+		return fmt.Errorf("timeBefore field %q not found in %s", param, parent)
+	}
+	return nil
+}
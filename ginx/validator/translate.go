@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+	"github.com/hchicken/pkg-go/ginx/response"
+)
+
+// 支持的语言
+const (
+	LangZH = "zh" // 中文，默认
+	LangEN = "en" // 英文
+)
+
+// langs 支持的语言
+var langs = []string{LangZH, LangEN}
+
+// uni 多语言翻译器，InitValidator 时初始化
+var uni *ut.UniversalTranslator
+
+// fieldName 错误中的字段名依次使用 json、form 标签，没有标签时使用字段名
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// registerTranslations 注册内置规则的中英文翻译
+func registerTranslations(v *validator.Validate) error {
+	zhLocale, enLocale := zh.New(), en.New()
+	uni = ut.New(zhLocale, zhLocale, enLocale)
+
+	zhTrans, _ := uni.GetTranslator(LangZH)
+	if err := zhTranslations.RegisterDefaultTranslations(v, zhTrans); err != nil {
+		return err
+	}
+	enTrans, _ := uni.GetTranslator(LangEN)
+	if err := enTranslations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Lang 根据 Accept-Language 选择语言，如 "en-US,en;q=0.9" 返回 en，不支持时返回 zh
+func Lang(acceptLanguage string) string {
+	for _, item := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(item, ";", 2)[0])
+		base := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		for _, lang := range langs {
+			if base == lang {
+				return lang
+			}
+		}
+	}
+	return LangZH
+}
+
+// Translate 将校验错误转换为字段错误列表，字段使用json名称，嵌套字段用 . 连接。
+// err 不是校验错误时返回nil
+func Translate(err error, lang string) []response.FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	var trans ut.Translator
+	if uni != nil {
+		trans, _ = uni.GetTranslator(lang)
+	}
+	fields := make([]response.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		field := fe.Field()
+		if _, ns, ok := strings.Cut(fe.Namespace(), "."); ok {
+			field = ns
+		}
+		message := fe.Error()
+		if trans != nil {
+			message = fe.Translate(trans)
+		}
+		fields = append(fields, response.FieldError{Field: field, Rule: fe.Tag(), Message: message})
+	}
+	return fields
+}
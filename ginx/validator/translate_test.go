@@ -0,0 +1,72 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hchicken/pkg-go/ginx/binding"
+	"github.com/hchicken/pkg-go/ginx/response"
+	"github.com/hchicken/pkg-go/ginx/validator"
+)
+
+type testAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type testUserReq struct {
+	Name     string      `json:"name" binding:"notBlank"`
+	Age      int         `json:"age" binding:"max=120"`
+	Birthday string      `json:"birthday" binding:"timeString"`
+	Address  testAddress `json:"address"`
+}
+
+// bindUser 绑定请求并返回响应中的字段错误
+func bindUser(t *testing.T, lang string) (int, []response.FieldError) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"name":"  ","age":200,"birthday":"2024/01/02","address":{}}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	c.Request.Header.Set("Accept-Language", lang)
+
+	var req testUserReq
+	if err := binding.ShouldBindJSON(c, &req); err == nil {
+		t.Fatal("expected validation error")
+	}
+	var resp struct {
+		Code   int                   `json:"code"`
+		Errors []response.FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return resp.Code, resp.Errors
+}
+
+func TestTranslate(t *testing.T) {
+	validator.InitValidator()
+
+	code, errs := bindUser(t, "en-US,en;q=0.9")
+	if code != response.CodeInvalidParams || len(errs) != 4 {
+		t.Fatalf("expected 4 field errors, got %d %+v", code, errs)
+	}
+	want := []response.FieldError{
+		{Field: "name", Rule: "notBlank", Message: "name cannot be blank"},
+		{Field: "age", Rule: "max", Message: "age must be 120 or less"},
+		{Field: "birthday", Rule: "timeString", Message: "birthday must be a time in the format 2006-01-02 15:04:05"},
+		{Field: "address.city", Rule: "required", Message: "city is a required field"},
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], errs[i])
+		}
+	}
+
+	if _, errs := bindUser(t, ""); errs[0].Message != "name不能为空" {
+		t.Errorf("expected zh message by default, got %+v", errs[0])
+	}
+}
@@ -1,16 +1,16 @@
 package validator
 
-import (
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
-)
+import "log"
 
-// InitValidator 初始化验证
+// InitValidator 初始化验证，注册自定义规则和中英文错误翻译，错误中的字段名使用json标签
 func InitValidator() {
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		_ = v.RegisterValidation("timeUnix", timeStringToUnix)
-		_ = v.RegisterValidation("trim", doTrimStringField)
-		_ = v.RegisterValidation("timeString", validTimeString)
-		_ = v.RegisterValidation("notBlank", notBlank)
+	if v, ok := engine(); ok {
+		v.RegisterTagNameFunc(fieldName)
+		if err := registerTranslations(v); err != nil {
+			log.Printf("Failed to register validator translations: %v", err)
+		}
+		if err := registerRules(v); err != nil {
+			log.Printf("Failed to register validator rules: %v", err)
+		}
 	}
 }
@@ -0,0 +1,347 @@
+package gormx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
+)
+
+// 审计动作
+const (
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// auditBeforeKey 更新、删除前记录快照在语句中的key
+const auditBeforeKey = "gormx:audit_before"
+
+// operatorKey 上下文中操作人的key
+type operatorKey struct{}
+
+// ContextWithOperator 在上下文中设置当前操作人
+func ContextWithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// OperatorFromContext 获取上下文中的操作人
+func OperatorFromContext(ctx context.Context) (string, bool) {
+	operator, ok := ctx.Value(operatorKey{}).(string)
+	return operator, ok && operator != ""
+}
+
+// AuditLog 审计日志，记录更新和删除前后的字段值
+type AuditLog struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Table     string    `json:"table" gorm:"type:varchar(128);column:table_name;index;comment:'表名'"`
+	RecordID  string    `json:"record_id" gorm:"type:varchar(128);index;comment:'记录主键'"`
+	Action    string    `json:"action" gorm:"type:varchar(16);comment:'动作'"`
+	Before    string    `json:"before" gorm:"type:text;comment:'变更前'"`
+	After     string    `json:"after" gorm:"type:text;comment:'变更后'"`
+	Operator  string    `json:"operator" gorm:"type:varchar(128);comment:'操作人'"`
+	CreatedAt time.Time `json:"created_at" gorm:"comment:'添加时间'"`
+}
+
+// TableName 审计日志表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// auditOptions 审计插件配置
+type auditOptions struct {
+	log      bool
+	operator func(context.Context) string
+}
+
+// AuditOption 审计插件配置项
+type AuditOption func(*auditOptions)
+
+// AuditLogEnabled 是否记录审计日志，开启前需迁移 AuditLog 表
+func AuditLogEnabled(b bool) AuditOption {
+	return func(o *auditOptions) {
+		o.log = b
+	}
+}
+
+// AuditOperator 自定义从上下文获取操作人的方法，默认使用 OperatorFromContext
+func AuditOperator(fn func(context.Context) string) AuditOption {
+	return func(o *auditOptions) {
+		o.operator = fn
+	}
+}
+
+// AuditPlugin 审计插件：创建时填充 created_by/updated_by，更新时填充 updated_by，
+// 开启审计日志后记录每次更新和删除前后的字段差异
+type AuditPlugin struct {
+	opts auditOptions
+}
+
+// NewAuditPlugin 创建审计插件，通过 db.Use 注册
+func NewAuditPlugin(opts ...AuditOption) *AuditPlugin {
+	o := auditOptions{
+		operator: func(ctx context.Context) string {
+			operator, _ := OperatorFromContext(ctx)
+			return operator
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &AuditPlugin{opts: o}
+}
+
+// Name 实现 gorm.Plugin
+func (p *AuditPlugin) Name() string {
+	return "gormx:audit"
+}
+
+// Initialize 实现 gorm.Plugin，注册回调
+func (p *AuditPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("gormx:audit_create", p.fillCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("gormx:audit_update", p.fillUpdate); err != nil {
+		return err
+	}
+	if !p.opts.log {
+		return nil
+	}
+
+	if err := db.Callback().Update().Before("gorm:update").Register("gormx:audit_before_update", p.snapshot); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("gormx:audit_after_update", p.record(AuditActionUpdate)); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("gormx:audit_before_delete", p.snapshot); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("gormx:audit_after_delete", p.record(AuditActionDelete))
+}
+
+// operator 当前语句的操作人
+func (p *AuditPlugin) operator(db *gorm.DB) string {
+	if db.Statement.Context == nil {
+		return ""
+	}
+	return p.opts.operator(db.Statement.Context)
+}
+
+// fillCreate 创建时为空的 created_by、updated_by 填充操作人
+func (p *AuditPlugin) fillCreate(db *gorm.DB) {
+	operator := p.operator(db)
+	if db.Error != nil || db.Statement.Schema == nil || operator == "" {
+		return
+	}
+
+	for _, name := range []string{"created_by", "updated_by"} {
+		field := db.Statement.Schema.LookUpField(name)
+		if field == nil {
+			continue
+		}
+		rv := db.Statement.ReflectValue
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				setIfZero(db, field, reflect.Indirect(rv.Index(i)), operator)
+			}
+		case reflect.Struct:
+			setIfZero(db, field, rv, operator)
+		}
+	}
+}
+
+// setIfZero 字段为零值时设置
+func setIfZero(db *gorm.DB, field *schema.Field, rv reflect.Value, value interface{}) {
+	if _, zero := field.ValueOf(db.Statement.Context, rv); zero {
+		db.AddError(field.Set(db.Statement.Context, rv, value))
+	}
+}
+
+// fillUpdate 更新时填充 updated_by，已显式指定时保留
+func (p *AuditPlugin) fillUpdate(db *gorm.DB) {
+	operator := p.operator(db)
+	if db.Error != nil || db.Statement.Schema == nil || operator == "" {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField("updated_by")
+	if field == nil {
+		return
+	}
+	if changes, ok := db.Statement.Dest.(map[string]interface{}); ok {
+		if _, set := changes[field.DBName]; set {
+			return
+		}
+		if _, set := changes[field.Name]; set {
+			return
+		}
+	}
+	db.Statement.SetColumn(field.DBName, operator, true)
+}
+
+// auditable 是否需要记录审计日志
+func auditable(db *gorm.DB) bool {
+	sch := db.Statement.Schema
+	return db.Error == nil && !db.DryRun && sch != nil && sch.PrioritizedPrimaryField != nil &&
+		sch.Table != (AuditLog{}).TableName()
+}
+
+// auditSession 审计使用的查询会话，沿用当前事务并强制主库
+func auditSession(db *gorm.DB) *gorm.DB {
+	tx := db.Session(&gorm.Session{NewDB: true}).Clauses(dbresolver.Write)
+	if db.Statement.Unscoped {
+		tx = tx.Unscoped()
+	}
+	return tx
+}
+
+// snapshot 更新、删除前查询受影响的记录
+func (p *AuditPlugin) snapshot(db *gorm.DB) {
+	if !auditable(db) {
+		return
+	}
+
+	stmt := db.Statement
+	var exprs []clause.Expression
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		exprs = append(exprs, where.Exprs...)
+	}
+	if stmt.ReflectValue.Kind() == reflect.Struct {
+		for _, field := range stmt.Schema.PrimaryFields {
+			if value, zero := field.ValueOf(stmt.Context, stmt.ReflectValue); !zero {
+				exprs = append(exprs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+			}
+		}
+	}
+	if len(exprs) == 0 && !db.AllowGlobalUpdate {
+		return
+	}
+
+	records := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	tx := auditSession(db).Table(stmt.Table)
+	if len(exprs) > 0 {
+		tx = tx.Clauses(clause.Where{Exprs: exprs})
+	}
+	if err := tx.Find(records.Interface()).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to load audit snapshot: %w", err))
+		return
+	}
+	db.InstanceSet(auditBeforeKey, records.Elem())
+}
+
+// record 更新、删除后写入审计日志
+func (p *AuditPlugin) record(action string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !auditable(db) || db.RowsAffected == 0 {
+			return
+		}
+		value, ok := db.InstanceGet(auditBeforeKey)
+		if !ok {
+			return
+		}
+		before := value.(reflect.Value)
+		if before.Len() == 0 {
+			return
+		}
+
+		sch := db.Statement.Schema
+		pk := sch.PrioritizedPrimaryField
+		after := map[string]map[string]interface{}{}
+		if action == AuditActionUpdate {
+			ids := make([]interface{}, before.Len())
+			for i := range ids {
+				ids[i], _ = pk.ValueOf(db.Statement.Context, before.Index(i))
+			}
+			records := reflect.New(before.Type())
+			err := auditSession(db).Table(db.Statement.Table).
+				Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids}).
+				Find(records.Interface()).Error
+			if err != nil {
+				db.AddError(fmt.Errorf("failed to load audit records: %w", err))
+				return
+			}
+			for i := 0; i < records.Elem().Len(); i++ {
+				id, values := recordValues(db, sch, records.Elem().Index(i))
+				after[id] = values
+			}
+		}
+
+		logs := make([]AuditLog, 0, before.Len())
+		for i := 0; i < before.Len(); i++ {
+			id, old := recordValues(db, sch, before.Index(i))
+			log := AuditLog{Table: sch.Table, RecordID: id, Action: action, Operator: p.operator(db)}
+
+			var changedBefore, changedAfter map[string]interface{}
+			if action == AuditActionUpdate {
+				changedBefore, changedAfter = diffValues(old, after[id])
+				if len(changedBefore) == 0 {
+					continue
+				}
+			} else {
+				changedBefore = old
+			}
+
+			var err error
+			if log.Before, err = marshalAudit(changedBefore); err != nil {
+				db.AddError(err)
+				return
+			}
+			if log.After, err = marshalAudit(changedAfter); err != nil {
+				db.AddError(err)
+				return
+			}
+			logs = append(logs, log)
+		}
+		if len(logs) == 0 {
+			return
+		}
+		if err := auditSession(db).Create(&logs).Error; err != nil {
+			db.AddError(fmt.Errorf("failed to write audit log: %w", err))
+		}
+	}
+}
+
+// recordValues 返回记录主键和各字段值
+func recordValues(db *gorm.DB, sch *schema.Schema, rv reflect.Value) (string, map[string]interface{}) {
+	rv = reflect.Indirect(rv)
+	values := make(map[string]interface{}, len(sch.Fields))
+	for _, field := range sch.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		values[field.DBName], _ = field.ValueOf(db.Statement.Context, rv)
+	}
+	return fmt.Sprint(values[sch.PrioritizedPrimaryField.DBName]), values
+}
+
+// diffValues 对比更新前后的字段，返回有变化字段的前后值
+func diffValues(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore, changedAfter := map[string]interface{}{}, map[string]interface{}{}
+	for name, old := range before {
+		value, ok := after[name]
+		if !ok || reflect.DeepEqual(old, value) {
+			continue
+		}
+		changedBefore[name], changedAfter[name] = old, value
+	}
+	return changedBefore, changedAfter
+}
+
+// marshalAudit 序列化审计字段，空值返回空字符串
+func marshalAudit(values map[string]interface{}) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit values: %w", err)
+	}
+	return string(b), nil
+}
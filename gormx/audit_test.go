@@ -0,0 +1,80 @@
+package gormx
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type testDocument struct {
+	ID        int64          `json:"id" gorm:"primaryKey"`
+	Title     string         `json:"title"`
+	CreatedBy string         `json:"created_by"`
+	UpdatedBy string         `json:"updated_by"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+func TestAuditPlugin(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "audit.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.Use(NewAuditPlugin(AuditLogEnabled(true))); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if err := db.AutoMigrate(&testDocument{}, &AuditLog{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	ctx := ContextWithOperator(context.Background(), "alice")
+	doc := &testDocument{Title: "draft"}
+	if err := db.WithContext(ctx).Create(doc).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if doc.CreatedBy != "alice" || doc.UpdatedBy != "alice" {
+		t.Errorf("expected created_by/updated_by filled, got %+v", doc)
+	}
+
+	ctx = ContextWithOperator(context.Background(), "bob")
+	err = NewDatabase(WithConnPool(db), WithConnContext(ctx), WithConnDbModel(&testDocument{}),
+		WithConnConditions(map[string]interface{}{"id": doc.ID}),
+		WithConnChanges(map[string]interface{}{"title": "final"})).Update()
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	d := NewDatabase(WithConnPool(db), WithConnContext(ctx), WithConnDbModel(&testDocument{}),
+		WithConnConditions(map[string]interface{}{"id": doc.ID}))
+	if err := d.Delete(); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	var total int64
+	if err := d.SetTotal(&total).Count(); err != nil || total != 0 {
+		t.Errorf("expected soft deleted record hidden, got %d, %v", total, err)
+	}
+	if err := d.SetUnscoped(true).Count(); err != nil || total != 1 {
+		t.Errorf("expected unscoped count to include deleted record, got %d, %v", total, err)
+	}
+
+	var logs []AuditLog
+	if err := db.Order("id").Find(&logs).Error; err != nil {
+		t.Fatalf("Find audit logs failed: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 audit logs, got %+v", logs)
+	}
+	update, remove := logs[0], logs[1]
+	if update.Action != AuditActionUpdate || update.Operator != "bob" || update.RecordID != "1" ||
+		!strings.Contains(update.Before, `"title":"draft"`) || !strings.Contains(update.After, `"title":"final"`) ||
+		!strings.Contains(update.After, `"updated_by":"bob"`) || strings.Contains(update.After, "created_by") {
+		t.Errorf("unexpected update audit log: %+v", update)
+	}
+	if remove.Action != AuditActionDelete || !strings.Contains(remove.Before, `"title":"final"`) || remove.After != "" {
+		t.Errorf("unexpected delete audit log: %+v", remove)
+	}
+}
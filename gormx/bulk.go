@@ -0,0 +1,102 @@
+package gormx
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultBatchSize 批量写入默认每批数量
+const defaultBatchSize = 500
+
+// BulkCreate 分批写入切片，设置冲突字段(ConflictKeys 或 UpdateName)时冲突记录按 onConflict 规则更新，
+// 多批写入在同一事务中执行
+func (d *Database) BulkCreate(values interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(values))
+	if rv.Kind() != reflect.Slice {
+		return fmt.Errorf("values must be a slice or a pointer to a slice")
+	}
+	if rv.Len() == 0 {
+		return nil
+	}
+	if err := d.checkContext(); err != nil {
+		return err
+	}
+
+	query := d.conn()
+	if len(d.opts.ConflictKeys) > 0 || d.opts.UpdateName != "" {
+		conflict, err := d.onConflict(query, values)
+		if err != nil {
+			return err
+		}
+		query = query.Clauses(conflict)
+	}
+
+	batchSize := d.opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if err := query.CreateInBatches(values, batchSize).Error; err != nil {
+		return err
+	}
+	d.invalidateCache(values)
+	return nil
+}
+
+// onConflict 生成冲突更新子句。冲突字段为 ConflictKeys，未设置时使用 UpdateName；
+// 设置 Values 时只更新这些字段；设置 UpdateAll 或 UpdateExcept 时更新除主键、冲突字段、创建时间、创建人和
+// UpdateExcept 外的所有字段；都未设置时冲突记录不更新。
+// MySQL 忽略冲突字段，按任意唯一索引冲突处理
+func (d *Database) onConflict(query *gorm.DB, model interface{}) (clause.OnConflict, error) {
+	stmt := &gorm.Statement{DB: query}
+	if err := stmt.Parse(model); err != nil {
+		return clause.OnConflict{}, fmt.Errorf("failed to parse model: %w", err)
+	}
+	sch := stmt.Schema
+
+	keys := d.opts.ConflictKeys
+	if len(keys) == 0 && d.opts.UpdateName != "" {
+		keys = []string{d.opts.UpdateName}
+	}
+
+	var conflict clause.OnConflict
+	skip := make(map[string]bool)
+	for _, key := range keys {
+		name := columnName(sch, key)
+		conflict.Columns = append(conflict.Columns, clause.Column{Name: name})
+		skip[name] = true
+	}
+
+	if len(d.opts.Values) > 0 {
+		columns := make([]string, 0, len(d.opts.Values))
+		for _, value := range d.opts.Values {
+			columns = append(columns, columnName(sch, value))
+		}
+		conflict.DoUpdates = clause.AssignmentColumns(columns)
+		return conflict, nil
+	}
+	if !d.opts.UpdateAll && len(d.opts.UpdateExcept) == 0 {
+		conflict.DoNothing = true
+		return conflict, nil
+	}
+
+	for _, field := range d.opts.UpdateExcept {
+		skip[columnName(sch, field)] = true
+	}
+	var columns []string
+	for _, field := range sch.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Creatable || skip[field.DBName] ||
+			field.AutoCreateTime > 0 || field.DBName == "created_at" || field.DBName == "created_by" {
+			continue
+		}
+		columns = append(columns, field.DBName)
+	}
+	if len(columns) == 0 {
+		conflict.DoNothing = true
+	} else {
+		conflict.DoUpdates = clause.AssignmentColumns(columns)
+	}
+	return conflict, nil
+}
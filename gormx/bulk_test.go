@@ -0,0 +1,89 @@
+package gormx
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+type testAccount struct {
+	ID        int64  `json:"id" gorm:"primaryKey"`
+	Tenant    string `json:"tenant" gorm:"uniqueIndex:idx_tenant_email"`
+	Email     string `json:"email" gorm:"uniqueIndex:idx_tenant_email"`
+	Name      string `json:"name"`
+	Note      string `json:"note"`
+	CreatedBy string `json:"created_by"`
+}
+
+func TestBulkCreateAndStream(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "bulk.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testAccount{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewRepository[testAccount](db)
+	accounts := make([]testAccount, 0, 5)
+	for _, email := range []string{"a", "b", "c", "d", "e"} {
+		accounts = append(accounts, testAccount{Tenant: "t1", Email: email, Name: "old", Note: "old", CreatedBy: "alice"})
+	}
+	if err := repo.BulkCreate(ctx, accounts, WithConnBatchSize(2)); err != nil {
+		t.Fatalf("BulkCreate failed: %v", err)
+	}
+
+	upserts := []testAccount{
+		{Tenant: "t1", Email: "a", Name: "new", Note: "new", CreatedBy: "bob"},
+		{Tenant: "t1", Email: "f", Name: "new", Note: "new", CreatedBy: "bob"},
+	}
+	err = repo.BulkCreate(ctx, upserts,
+		WithConnConflictKeys([]string{"Tenant", "email"}),
+		WithConnUpdateExcept([]string{"note"}),
+	)
+	if err != nil {
+		t.Fatalf("BulkCreate upsert failed: %v", err)
+	}
+
+	var got testAccount
+	if err := db.Where("email = ?", "a").First(&got).Error; err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if got.Name != "new" || got.Note != "old" || got.CreatedBy != "alice" {
+		t.Errorf("expected name updated and note/created_by kept, got %+v", got)
+	}
+
+	// 仅指定冲突字段时冲突记录不更新，显式设置 UpdateAll 后更新
+	conflict := []testAccount{{Tenant: "t1", Email: "b", Name: "skip", Note: "skip"}}
+	if err := repo.BulkCreate(ctx, conflict, WithConnConflictKeys([]string{"tenant", "email"})); err != nil {
+		t.Fatalf("BulkCreate do nothing failed: %v", err)
+	}
+	var kept testAccount
+	if err := db.Where("email = ?", "b").First(&kept).Error; err != nil || kept.Name != "old" {
+		t.Errorf("expected conflict row kept, got %+v, %v", kept, err)
+	}
+	conflict[0].Name = "all"
+	err = repo.BulkCreate(ctx, conflict, WithConnConflictKeys([]string{"tenant", "email"}), WithConnUpdateAll(true))
+	if err != nil {
+		t.Fatalf("BulkCreate update all failed: %v", err)
+	}
+	var updated testAccount
+	if err := db.Where("email = ?", "b").First(&updated).Error; err != nil || updated.Name != "all" || updated.CreatedBy != "alice" {
+		t.Errorf("expected conflict row updated, got %+v, %v", updated, err)
+	}
+
+	var batches, total int
+	err = repo.Stream(ctx, 4, func(items []testAccount) error {
+		batches++
+		total += len(items)
+		return nil
+	}, WithConnConditions(map[string]interface{}{"tenant": "t1"}))
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if batches != 2 || total != 6 {
+		t.Errorf("expected 6 records in 2 batches, got %d in %d", total, batches)
+	}
+}
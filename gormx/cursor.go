@@ -0,0 +1,269 @@
+package gormx
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// cursorSchemaCache 查询结果模型的schema缓存
+var cursorSchemaCache = &sync.Map{}
+
+// CursorPage 游标分页结果
+type CursorPage struct {
+	NextCursor string `json:"next_cursor"` // 下一页游标，没有更多数据时为空
+	HasMore    bool   `json:"has_more"`    // 是否还有更多数据
+}
+
+// cursorValue 游标中的排序字段值，时间单独保存以便还原类型
+type cursorValue struct {
+	Time  *time.Time  `json:"t,omitempty"`
+	Value interface{} `json:"v,omitempty"`
+}
+
+// encodeCursor 将排序字段值编码为不透明的base64游标
+func encodeCursor(values []interface{}) (string, error) {
+	items := make([]cursorValue, len(values))
+	for i, value := range values {
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return "", err
+			}
+			value = v
+		}
+		if t, ok := value.(time.Time); ok {
+			items[i].Time = &t
+		} else {
+			items[i].Value = value
+		}
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor 解码游标
+func decodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var items []cursorValue
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		if item.Time != nil {
+			values[i] = *item.Time
+			continue
+		}
+		values[i] = item.Value
+		if number, ok := item.Value.(json.Number); ok {
+			if n, err := number.Int64(); err == nil {
+				values[i] = n
+			} else if f, err := number.Float64(); err == nil {
+				values[i] = f
+			}
+		}
+	}
+	return values, nil
+}
+
+// cursorSorts 返回游标分页使用的排序字段，末尾追加主键保证排序唯一
+func (d *Database) cursorSorts(query *gorm.DB) ([]Sort, error) {
+	if err := query.Statement.Parse(d.opts.DbModel); err != nil {
+		return nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+	sch := query.Statement.Schema
+
+	var sorts []Sort
+	if d.opts.Sort != "" {
+		parsed, err := ParseSort(d.opts.Sort)
+		if err != nil {
+			return nil, err
+		}
+		resolve := d.sortResolver(query)
+		for _, sort := range parsed {
+			if sort.Column, err = resolve(sort.Column); err != nil {
+				return nil, fmt.Errorf("invalid sort field: %w", err)
+			}
+			sorts = append(sorts, sort)
+		}
+	} else {
+		sortField := d.opts.SortField
+		if sortField == "" {
+			sortField = "id DESC"
+		}
+		parsed, err := parseSortField(sortField)
+		if err != nil {
+			return nil, err
+		}
+		for _, sort := range parsed {
+			field := sch.LookUpField(sort.Column)
+			if field == nil || field.DBName == "" {
+				return nil, fmt.Errorf("cursor sort field %s not found in model", sort.Column)
+			}
+			sort.Column = field.DBName
+			sorts = append(sorts, sort)
+		}
+	}
+
+	for _, sort := range sorts {
+		if sort.Nulls != NullsDefault {
+			return nil, fmt.Errorf("cursor pagination does not support nulls order on %s", sort.Column)
+		}
+	}
+
+	if pk := sch.PrioritizedPrimaryField; pk != nil {
+		for _, sort := range sorts {
+			if sort.Column == pk.DBName {
+				return sorts, nil
+			}
+		}
+		desc := len(sorts) > 0 && sorts[len(sorts)-1].Desc
+		sorts = append(sorts, Sort{Column: pk.DBName, Desc: desc})
+	}
+	return sorts, nil
+}
+
+// parseSortField 解析 "created_at DESC, id" 格式的排序字段
+func parseSortField(sortField string) ([]Sort, error) {
+	var sorts []Sort
+	for _, item := range strings.Split(sortField, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 {
+			continue
+		}
+		sort := Sort{Column: strings.Trim(parts[0], "`\"")}
+		switch {
+		case len(parts) == 1:
+		case len(parts) == 2 && strings.EqualFold(parts[1], "desc"):
+			sort.Desc = true
+		case len(parts) == 2 && strings.EqualFold(parts[1], "asc"):
+		default:
+			return nil, fmt.Errorf("cursor pagination requires column sort, got %q", item)
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+// cursorCondition 生成游标之后的记录条件:
+// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...，降序字段使用 <
+func cursorCondition(sorts []Sort, values []interface{}) (clause.Expression, error) {
+	if len(values) != len(sorts) {
+		return nil, fmt.Errorf("%w: expected %d values, got %d", ErrInvalidCursor, len(sorts), len(values))
+	}
+
+	ors := make([]clause.Expression, 0, len(sorts))
+	for i, sort := range sorts {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: clause.Column{Name: sorts[j].Column}, Value: values[j]})
+		}
+		column := clause.Column{Name: sort.Column}
+		if sort.Desc {
+			ands = append(ands, clause.Lt{Column: column, Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: column, Value: values[i]})
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	if len(ors) == 1 {
+		// 单个 OR 条件会被 gorm 以 OR 连接到之前的查询条件
+		return ors[0], nil
+	}
+	return clause.Or(ors...), nil
+}
+
+// executeCursorQuery 按游标分页执行查询并填充 CursorPage
+func (d *Database) executeCursorQuery(query *gorm.DB, operation func(*gorm.DB) *gorm.DB) error {
+	if d.opts.Limit <= 0 {
+		return fmt.Errorf("limit must be set for cursor pagination")
+	}
+
+	sorts, err := d.cursorSorts(query)
+	if err != nil {
+		return err
+	}
+
+	if d.opts.Cursor != "" {
+		values, err := decodeCursor(d.opts.Cursor)
+		if err != nil {
+			return err
+		}
+		condition, err := cursorCondition(sorts, values)
+		if err != nil {
+			return err
+		}
+		query = query.Where(condition)
+	}
+
+	order, err := buildOrder(sorts, func(name string) (clause.Column, error) { return clause.Column{Name: name}, nil })
+	if err != nil {
+		return err
+	}
+	query = query.Clauses(clause.OrderBy{Expression: order}).Limit(d.opts.Limit + 1)
+
+	if err := operation(query).Error; err != nil {
+		return err
+	}
+	return d.fillCursorPage(sorts)
+}
+
+// fillCursorPage 截断多查询的一条记录，并根据最后一条记录生成下一页游标
+func (d *Database) fillCursorPage(sorts []Sort) error {
+	page := d.opts.CursorPage
+	*page = CursorPage{}
+
+	rv := reflect.ValueOf(d.opts.ScanModel)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("scan model must be a pointer to a slice for cursor pagination")
+	}
+	items := rv.Elem()
+	if items.Len() <= d.opts.Limit {
+		return nil
+	}
+	items.Set(items.Slice(0, d.opts.Limit))
+	page.HasMore = true
+
+	elemType := items.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	sch, err := schema.Parse(reflect.New(elemType).Interface(), cursorSchemaCache, d.db.NamingStrategy)
+	if err != nil {
+		return fmt.Errorf("failed to parse scan model: %w", err)
+	}
+
+	last := reflect.Indirect(items.Index(items.Len() - 1))
+	values := make([]interface{}, len(sorts))
+	for i, sort := range sorts {
+		field := sch.LookUpField(sort.Column)
+		if field == nil {
+			return fmt.Errorf("cursor sort field %s not found in scan model", sort.Column)
+		}
+		values[i], _ = field.ValueOf(d.Context(), last)
+	}
+
+	page.NextCursor, err = encodeCursor(values)
+	return err
+}
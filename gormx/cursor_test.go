@@ -0,0 +1,94 @@
+package gormx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	cursor, err := encodeCursor([]interface{}{JsonTime{Time: created}, int64(42), "tom"})
+	if err != nil {
+		t.Fatalf("encodeCursor failed: %v", err)
+	}
+
+	values, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodeCursor failed: %v", err)
+	}
+	if got, ok := values[0].(time.Time); !ok || !got.Equal(created) {
+		t.Errorf("expected time %v, got %#v", created, values[0])
+	}
+	if values[1] != int64(42) || values[2] != "tom" {
+		t.Errorf("unexpected values: %#v", values[1:])
+	}
+
+	if _, err := decodeCursor("not a cursor"); err == nil {
+		t.Error("expected error for invalid cursor")
+	}
+}
+
+func TestDatabaseCursor(t *testing.T) {
+	db := dryRunDB(t)
+	cursor, _ := encodeCursor([]interface{}{30, 7})
+	var (
+		users []testUser
+		page  CursorPage
+	)
+	d := NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}), WithConnScanModel(&users),
+		WithConnSort("-age"), WithConnLimit(10), WithConnCursor(cursor), WithConnCursorPage(&page))
+	query, err := d.prepareQuery()
+	if err != nil {
+		t.Fatalf("prepareQuery failed: %v", err)
+	}
+
+	var sql string
+	err = d.executeCursorQuery(query, func(query *gorm.DB) *gorm.DB {
+		query = query.Find(&users)
+		sql = query.Statement.SQL.String()
+		return query
+	})
+	if err != nil {
+		t.Fatalf("executeCursorQuery failed: %v", err)
+	}
+
+	want := "WHERE (`age` < ? OR (`age` = ? AND `id` < ?)) ORDER BY `age` DESC,`id` DESC LIMIT 11"
+	if !strings.Contains(sql, want) {
+		t.Errorf("expected %q in %s", want, sql)
+	}
+}
+
+func TestDatabaseCursorConditions(t *testing.T) {
+	db := dryRunDB(t)
+	cursor, _ := encodeCursor([]interface{}{7})
+	var (
+		users []testUser
+		page  CursorPage
+	)
+	d := NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}), WithConnScanModel(&users),
+		WithConnConditions(map[string]interface{}{"name": "tom"}), WithConnSort("id"),
+		WithConnLimit(10), WithConnCursor(cursor), WithConnCursorPage(&page))
+	query, err := d.prepareQuery()
+	if err != nil {
+		t.Fatalf("prepareQuery failed: %v", err)
+	}
+
+	var sql string
+	err = d.executeCursorQuery(query, func(query *gorm.DB) *gorm.DB {
+		query = query.Find(&users)
+		sql = query.Statement.SQL.String()
+		return query
+	})
+	if err != nil {
+		t.Fatalf("executeCursorQuery failed: %v", err)
+	}
+
+	// 单个排序字段的游标条件使用 AND 连接查询条件
+	want := "WHERE `name` = ? AND `id` > ? ORDER BY `id` LIMIT 11"
+	if !strings.Contains(sql, want) {
+		t.Errorf("expected %q in %s", want, sql)
+	}
+}
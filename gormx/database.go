@@ -1,18 +1,25 @@
 package gormx
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"time"
+
+	"github.com/hchicken/pkg-go/cache"
 
 	"github.com/hchicken/pkg-go/util"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 )
 
 // Database represents a database connection and operations
 type Database struct {
-	db   *gorm.DB
-	opts ConnectionOptions
+	db    *gorm.DB
+	opts  ConnectionOptions
+	exprs []clause.Expression // Repository 追加的条件，如主键
 }
 
 // NewDatabase creates a new Database instance
@@ -24,6 +31,44 @@ func NewDatabase(opts ...ConnectionOption) *Database {
 	}
 }
 
+// conn 返回携带上下文的连接，上下文中有事务时优先使用事务
+func (d *Database) conn() *gorm.DB {
+	db := d.db
+	if d.opts.Ctx != nil {
+		db = dbWithContext(d.opts.Ctx, db)
+	}
+	if d.opts.Primary {
+		db = db.Clauses(dbresolver.Write)
+	}
+	if d.opts.Unscoped {
+		db = db.Unscoped()
+	}
+	return db
+}
+
+// Context 获取上下文，未设置时返回 context.Background。
+// WithTx 中的上下文携带事务，传给 Repository 时在同一事务中执行
+func (d *Database) Context() context.Context {
+	if d.opts.Ctx != nil {
+		return d.opts.Ctx
+	}
+	return context.Background()
+}
+
+// where 追加查询条件，不受 ExcludeFields、Like、In 影响
+func (d *Database) where(expr clause.Expression) *Database {
+	d.exprs = append(d.exprs, expr)
+	return d
+}
+
+// checkContext 上下文已取消时提前返回
+func (d *Database) checkContext() error {
+	if d.opts.Ctx != nil {
+		return d.opts.Ctx.Err()
+	}
+	return nil
+}
+
 func (d *Database) decodeAndCleanConditions() (map[string]interface{}, error) {
 	var conditions map[string]interface{}
 	err := util.StructDecode(d.opts.Conditions, &conditions)
@@ -41,12 +86,19 @@ func (d *Database) decodeAndCleanConditions() (map[string]interface{}, error) {
 
 // prepareQuery sets up the base query with conditions
 func (d *Database) prepareQuery() (*gorm.DB, error) {
+	if err := d.checkContext(); err != nil {
+		return nil, err
+	}
+
 	conditions, err := d.decodeAndCleanConditions()
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode conditions: %w", err)
 	}
 
-	query := d.db.Model(d.opts.DbModel)
+	query := d.conn().Model(d.opts.DbModel)
+	if d.opts.Debug {
+		query = query.Debug()
+	}
 
 	// Apply LIKE conditions
 	for _, field := range d.opts.Like {
@@ -66,13 +118,21 @@ func (d *Database) prepareQuery() (*gorm.DB, error) {
 
 	// Apply remaining conditions
 	query = query.Where(conditions)
+	for _, expr := range d.exprs {
+		query = query.Where(expr)
+	}
 
 	// Apply time range if specified
 	if d.opts.StartTime != "" && d.opts.EndTime != "" {
-		query = query.Where("created_at BETWEEN ? AND ?", d.opts.StartTime, d.opts.EndTime)
+		timeField := d.opts.TimeField
+		if timeField == "" {
+			timeField = "created_at"
+		}
+		query = query.Where("? BETWEEN ? AND ?", clause.Column{Name: timeField}, d.opts.StartTime, d.opts.EndTime)
 	}
 
-	return query, nil
+	// Apply structured filters
+	return d.applyFilters(query)
 }
 
 // applyPagination adds limit and offset to the query
@@ -88,13 +148,17 @@ func (d *Database) applyPagination(query *gorm.DB) *gorm.DB {
 	return query
 }
 
-// applyOrder adds Order to the query
-func (d *Database) applyOrder(query *gorm.DB) *gorm.DB {
+// applyOrder adds Order to the query.
+// Sort is validated against the allowed fields, SortField is used as is
+func (d *Database) applyOrder(query *gorm.DB) (*gorm.DB, error) {
+	if d.opts.Sort != "" {
+		return d.applySort(query)
+	}
 	if d.opts.SortField == "" {
 		d.opts.SortField = "id DESC"
 	}
 	query = query.Order(d.opts.SortField)
-	return query
+	return query, nil
 }
 
 // executeQuery prepares and executes a query with the given operation
@@ -110,59 +174,206 @@ func (d *Database) executeQuery(operation func(*gorm.DB) *gorm.DB) error {
 		}
 	}
 
-	query = d.applyPagination(query)
-	query = d.applyOrder(query)
+	if d.opts.CursorPage != nil {
+		return d.executeCursorQuery(query, operation)
+	}
 
-	if d.opts.Debug {
-		query = query.Debug()
+	query = d.applyPagination(query)
+	if query, err = d.applyOrder(query); err != nil {
+		return err
 	}
 
 	return operation(query).Error
 }
 
-// Query retrieves records based on the set options
+// Query retrieves records based on the set options, using the query cache when Cache is set
 func (d *Database) Query() error {
-	return d.executeQuery(func(query *gorm.DB) *gorm.DB {
-		return query.Scan(d.opts.ScanModel)
+	return d.cachedQuery("query", func() error {
+		return d.executeQuery(func(query *gorm.DB) *gorm.DB {
+			return query.Scan(d.opts.ScanModel)
+		})
 	})
 }
 
-// First retrieves the first record that matches the query
+// First retrieves the first record that matches the query, using the query cache when Cache is set
 func (d *Database) First() error {
-	return d.executeQuery(func(query *gorm.DB) *gorm.DB {
-		return query.First(d.opts.ScanModel)
+	return d.cachedQuery("first", func() error {
+		return d.executeQuery(func(query *gorm.DB) *gorm.DB {
+			return query.First(d.opts.ScanModel)
+		})
 	})
 }
 
+// Count stores the number of records that match the query into Total
+func (d *Database) Count() error {
+	if d.opts.Total == nil {
+		return fmt.Errorf("total must be set before count")
+	}
+	query, err := d.prepareQuery()
+	if err != nil {
+		return err
+	}
+	return query.Count(d.opts.Total).Error
+}
+
 // Create adds a new record to the database
 func (d *Database) Create(value interface{}) error {
 	if reflect.TypeOf(value).Kind() != reflect.Ptr {
 		return fmt.Errorf("value must be a pointer to a struct")
 	}
-	return d.db.Create(value).Error
+	if err := d.checkContext(); err != nil {
+		return err
+	}
+	if err := d.conn().Create(value).Error; err != nil {
+		return err
+	}
+	d.invalidateCache(value)
+	return nil
 }
 
 // CreateOrUpdate adds a new record or updates an existing one
 func (d *Database) CreateOrUpdate() error {
-	return d.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: d.opts.UpdateName}},
-		DoUpdates: clause.AssignmentColumns(d.opts.Values),
-	}).Create(d.opts.DbModel).Error
+	if err := d.checkContext(); err != nil {
+		return err
+	}
+	query := d.conn()
+	conflict, err := d.onConflict(query, d.opts.DbModel)
+	if err != nil {
+		return err
+	}
+	if err := query.Clauses(conflict).Create(d.opts.DbModel).Error; err != nil {
+		return err
+	}
+	d.invalidateCache(d.opts.DbModel)
+	return nil
 }
 
-// Update modifies existing records (placeholder for future implementation)
+// Update modifies the records that match the query.
+// Changes are taken from SetChanges, or from the non-zero fields of DbModel;
+// Values acts as a field mask and allows zero values to be written.
 func (d *Database) Update() error {
-	// TODO: Implement update logic
+	query, err := d.prepareQuery()
+	if err != nil {
+		return err
+	}
+	if err := query.Statement.Parse(d.opts.DbModel); err != nil {
+		return fmt.Errorf("failed to parse model: %w", err)
+	}
+
+	changes, err := d.buildChanges(query.Statement.Schema)
+	if err != nil {
+		return err
+	}
+
+	if d.opts.VersionField != "" {
+		if query, err = d.applyVersion(query, query.Statement.Schema, changes); err != nil {
+			return err
+		}
+	}
+
+	result := query.Updates(changes)
+	if result.Error != nil {
+		return result.Error
+	}
+	if d.opts.RowsAffected != nil {
+		*d.opts.RowsAffected = result.RowsAffected
+	}
+	if d.opts.VersionField != "" && result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	d.invalidateCache(d.opts.DbModel)
 	return nil
 }
 
+// buildChanges collects the columns to update, keyed by column name
+func (d *Database) buildChanges(sch *schema.Schema) (map[string]interface{}, error) {
+	mask := make(map[string]bool, len(d.opts.Values))
+	for _, name := range d.opts.Values {
+		mask[columnName(sch, name)] = true
+	}
+
+	changes := make(map[string]interface{})
+	if d.opts.Changes != nil {
+		for name, value := range d.opts.Changes {
+			column := columnName(sch, name)
+			if len(mask) == 0 || mask[column] {
+				changes[column] = value
+			}
+		}
+	} else {
+		rv := reflect.Indirect(reflect.ValueOf(d.opts.DbModel))
+		if rv.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("model must be a pointer to a struct when changes are not set")
+		}
+		for _, field := range sch.Fields {
+			if field.DBName == "" || field.PrimaryKey || !field.Updatable {
+				continue
+			}
+			value, isZero := field.ValueOf(d.Context(), rv)
+			if (len(mask) == 0 && !isZero) || mask[field.DBName] {
+				changes[field.DBName] = value
+			}
+		}
+	}
+
+	for _, field := range d.opts.OmitFields {
+		delete(changes, columnName(sch, field))
+	}
+
+	if d.opts.Operator != "" {
+		if field := sch.LookUpField("updated_by"); field != nil {
+			changes[field.DBName] = d.opts.Operator
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+	return changes, nil
+}
+
+// applyVersion adds the optimistic lock condition and increments the version column
+func (d *Database) applyVersion(query *gorm.DB, sch *schema.Schema, changes map[string]interface{}) (*gorm.DB, error) {
+	field := sch.LookUpField(d.opts.VersionField)
+	if field == nil {
+		return nil, fmt.Errorf("version field %s not found", d.opts.VersionField)
+	}
+
+	var current interface{}
+	if d.opts.Version != nil {
+		current = *d.opts.Version
+	} else {
+		rv := reflect.Indirect(reflect.ValueOf(d.opts.DbModel))
+		if rv.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("version must be set when model is not a struct")
+		}
+		current, _ = field.ValueOf(d.Context(), rv)
+	}
+
+	column := clause.Column{Name: field.DBName}
+	changes[field.DBName] = gorm.Expr("? + 1", column)
+	return query.Where(clause.Eq{Column: column, Value: current}), nil
+}
+
+// columnName resolves a struct field name or column name to the column name
+func columnName(sch *schema.Schema, name string) string {
+	if field := sch.LookUpField(name); field != nil && field.DBName != "" {
+		return field.DBName
+	}
+	return name
+}
+
 // Delete removes records based on the set conditions
 func (d *Database) Delete() error {
 	query, err := d.prepareQuery()
 	if err != nil {
 		return err
 	}
-	return query.Delete(d.opts.DbModel).Error
+	if err := query.Delete(d.opts.DbModel).Error; err != nil {
+		return err
+	}
+	d.invalidateCache(d.opts.DbModel)
+	return nil
 }
 
 // Setter methods for Database - 动态配置方法
@@ -174,6 +385,12 @@ func (d *Database) SetPool(pool *gorm.DB) *Database {
 	return d
 }
 
+// SetContext 设置上下文
+func (d *Database) SetContext(ctx context.Context) *Database {
+	d.opts.Ctx = ctx
+	return d
+}
+
 // SetDbModel 设置DB模型
 func (d *Database) SetDbModel(model interface{}) *Database {
 	d.opts.DbModel = model
@@ -240,12 +457,30 @@ func (d *Database) SetPage(page int) *Database {
 	return d
 }
 
+// SetCursor 设置游标，需同时设置 SetCursorPage 和 SetLimit
+func (d *Database) SetCursor(cursor string) *Database {
+	d.opts.Cursor = cursor
+	return d
+}
+
+// SetCursorPage 设置游标分页结果指针，设置后使用游标分页代替 offset 分页
+func (d *Database) SetCursorPage(page *CursorPage) *Database {
+	d.opts.CursorPage = page
+	return d
+}
+
 // SetSortField 设置排序字段
 func (d *Database) SetSortField(field string) *Database {
 	d.opts.SortField = field
 	return d
 }
 
+// SetSort 设置排序规则，如 "-created_at,name"，字段需在允许范围内
+func (d *Database) SetSort(spec string) *Database {
+	d.opts.Sort = spec
+	return d
+}
+
 // SetStartTime 设置开始时间
 func (d *Database) SetStartTime(t string) *Database {
 	d.opts.StartTime = t
@@ -258,18 +493,138 @@ func (d *Database) SetEndTime(t string) *Database {
 	return d
 }
 
+// SetTimeField 设置时间范围字段
+func (d *Database) SetTimeField(field string) *Database {
+	d.opts.TimeField = field
+	return d
+}
+
+// SetFilters 设置过滤条件
+func (d *Database) SetFilters(filters ...Filter) *Database {
+	d.opts.Filters = filters
+	return d
+}
+
+// AddFilters 追加过滤条件
+func (d *Database) AddFilters(filters ...Filter) *Database {
+	d.opts.Filters = append(d.opts.Filters, filters...)
+	return d
+}
+
+// SetFilterStruct 设置带filter标签的过滤结构体
+func (d *Database) SetFilterStruct(v interface{}) *Database {
+	d.opts.FilterStruct = v
+	return d
+}
+
+// SetAllowedFields 设置允许过滤和排序的字段，未设置时只允许模型中存在的字段
+func (d *Database) SetAllowedFields(fields []string) *Database {
+	d.opts.AllowedFields = fields
+	return d
+}
+
+// SetSortFields 设置允许排序的字段
+func (d *Database) SetSortFields(fields []string) *Database {
+	d.opts.SortFields = fields
+	return d
+}
+
 // SetUpdateName 设置更新字段key
 func (d *Database) SetUpdateName(name string) *Database {
 	d.opts.UpdateName = name
 	return d
 }
 
+// SetConflictKeys 设置冲突字段
+func (d *Database) SetConflictKeys(keys []string) *Database {
+	d.opts.ConflictKeys = keys
+	return d
+}
+
+// SetUpdateAll 设置冲突时是否更新所有字段
+func (d *Database) SetUpdateAll(b bool) *Database {
+	d.opts.UpdateAll = b
+	return d
+}
+
+// SetUpdateExcept 设置冲突时不更新的字段
+func (d *Database) SetUpdateExcept(fields []string) *Database {
+	d.opts.UpdateExcept = fields
+	return d
+}
+
+// SetBatchSize 设置批量写入每批数量
+func (d *Database) SetBatchSize(size int) *Database {
+	d.opts.BatchSize = size
+	return d
+}
+
 // SetValues 设置更新的values
 func (d *Database) SetValues(v []string) *Database {
 	d.opts.Values = v
 	return d
 }
 
+// SetChanges 设置更新内容
+func (d *Database) SetChanges(changes map[string]interface{}) *Database {
+	d.opts.Changes = changes
+	return d
+}
+
+// SetOmitFields 设置更新时不写入的字段
+func (d *Database) SetOmitFields(fields []string) *Database {
+	d.opts.OmitFields = fields
+	return d
+}
+
+// SetOperator 设置操作人，更新时写入 updated_by
+func (d *Database) SetOperator(operator string) *Database {
+	d.opts.Operator = operator
+	return d
+}
+
+// SetVersionField 设置乐观锁版本字段
+func (d *Database) SetVersionField(field string) *Database {
+	d.opts.VersionField = field
+	return d
+}
+
+// SetVersion 设置乐观锁当前版本，未设置时从DbModel读取
+func (d *Database) SetVersion(version int64) *Database {
+	d.opts.Version = &version
+	return d
+}
+
+// SetRowsAffected 设置影响行数指针
+func (d *Database) SetRowsAffected(rows *int64) *Database {
+	d.opts.RowsAffected = rows
+	return d
+}
+
+// SetPrimary 设置是否强制查询主库，用于写后读一致性
+func (d *Database) SetPrimary(b bool) *Database {
+	d.opts.Primary = b
+	return d
+}
+
+// SetUnscoped 设置是否忽略软删除
+func (d *Database) SetUnscoped(b bool) *Database {
+	d.opts.Unscoped = b
+	return d
+}
+
+// SetCache 设置查询缓存，Query/First 的结果缓存到 cache，写操作使缓存失效
+func (d *Database) SetCache(pool cache.CachePool) *Database {
+	d.opts.Cache = pool
+	return d
+}
+
+// SetCacheTTL 设置查询缓存过期时间，默认1分钟
+func (d *Database) SetCacheTTL(ttl time.Duration) *Database {
+	d.opts.CacheTTL = ttl
+	return d
+}
+
 // SetDebug 设置调试模式
 func (d *Database) SetDebug(b bool) *Database {
 	d.opts.Debug = b
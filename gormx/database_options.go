@@ -1,35 +1,66 @@
 package gormx
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"time"
+
+	"github.com/hchicken/pkg-go/cache"
+
+	"gorm.io/gorm"
+)
 
 // ConnectionOption ...
 type ConnectionOption func(*ConnectionOptions)
 
 // ConnectionOptions db查询
 type ConnectionOptions struct {
-	Pool          *gorm.DB    // pool
-	DbModel       interface{} // DB结构体
-	ScanModel     interface{} // 查询结果
-	Conditions    interface{} // 查询条件
-	ExcludeFields []string    // 不查询的字段
-	In            []string    // in查询
-	Like          []string    // like的查询条件
-	Page          int         // 页码
-	Limit         int         // 查询数量
-	Offset        int         // 偏移量
-	Total         *int64      // 总数
-	SortField     string      // 排序
-	StartTime     string      // 开始时间
-	EndTime       string      // 结束时间
-	UpdateName    string      // 更新key
-	Values        []string    // 更新字段
-
-	Debug bool // 是否debug查询
+	Pool          *gorm.DB               // pool
+	Ctx           context.Context        // 上下文
+	DbModel       interface{}            // DB结构体
+	ScanModel     interface{}            // 查询结果
+	Conditions    interface{}            // 查询条件
+	ExcludeFields []string               // 不查询的字段
+	In            []string               // in查询
+	Like          []string               // like的查询条件
+	Page          int                    // 页码
+	Limit         int                    // 查询数量
+	Offset        int                    // 偏移量
+	Total         *int64                 // 总数
+	Cursor        string                 // 游标
+	CursorPage    *CursorPage            // 游标分页结果
+	SortField     string                 // 排序
+	Sort          string                 // 排序规则，字段经过白名单校验
+	StartTime     string                 // 开始时间
+	EndTime       string                 // 结束时间
+	TimeField     string                 // 时间范围字段
+	Filters       []Filter               // 过滤条件
+	FilterStruct  interface{}            // 带filter标签的过滤结构体
+	AllowedFields []string               // 允许过滤的字段，未设置 SortFields 时同样用于排序
+	SortFields    []string               // 允许排序的字段
+	UpdateName    string                 // 更新key
+	ConflictKeys  []string               // 冲突字段，支持多列，未设置时使用 UpdateName
+	UpdateAll     bool                   // 冲突时未指定 Values 则更新所有字段
+	UpdateExcept  []string               // 更新所有字段时排除的字段，设置后等同于 UpdateAll
+	BatchSize     int                    // 批量写入每批数量
+	Values        []string               // 更新字段
+	Changes       map[string]interface{} // 更新内容
+	OmitFields    []string               // 更新时不写入的字段
+	Operator      string                 // 操作人
+	VersionField  string                 // 乐观锁版本字段
+	Version       *int64                 // 乐观锁当前版本
+	RowsAffected  *int64                 // 影响行数
+	Cache         cache.CachePool        // 查询缓存
+	CacheTTL      time.Duration          // 查询缓存过期时间
+
+	Primary  bool // 是否强制查询主库
+	Unscoped bool // 是否忽略软删除，查询包含已删除记录，删除时物理删除
+	Debug    bool // 是否debug查询
 }
 
 func newConnectionOptions(opts ...ConnectionOption) ConnectionOptions {
 	opt := ConnectionOptions{
-		ExcludeFields: []string{"limit", "page", "sort", "s_time", "e_time"},
+		ExcludeFields: []string{"limit", "page", "sort", "cursor", "s_time", "e_time"},
+		TimeField:     "created_at",
 	}
 	for _, o := range opts {
 		o(&opt)
@@ -44,6 +75,13 @@ func WithConnPool(pool *gorm.DB) ConnectionOption {
 	}
 }
 
+// WithConnContext 上下文
+func WithConnContext(ctx context.Context) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Ctx = ctx
+	}
+}
+
 // WithConnDbModel DB模型
 func WithConnDbModel(model interface{}) ConnectionOption {
 	return func(o *ConnectionOptions) {
@@ -107,6 +145,20 @@ func WithConnOffset(offset int) ConnectionOption {
 	}
 }
 
+// WithConnCursor 游标
+func WithConnCursor(cursor string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Cursor = cursor
+	}
+}
+
+// WithConnCursorPage 游标分页结果
+func WithConnCursorPage(page *CursorPage) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.CursorPage = page
+	}
+}
+
 // WithConnPage 页码
 func WithConnPage(page int) ConnectionOption {
 	return func(o *ConnectionOptions) {
@@ -121,6 +173,13 @@ func WithConnSortField(field string) ConnectionOption {
 	}
 }
 
+// WithConnSort 排序规则，如 "-created_at,name:nulls_last"
+func WithConnSort(spec string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Sort = spec
+	}
+}
+
 // WithConnStartTime 开始时间
 func WithConnStartTime(t string) ConnectionOption {
 	return func(o *ConnectionOptions) {
@@ -135,6 +194,41 @@ func WithConnEndTime(t string) ConnectionOption {
 	}
 }
 
+// WithConnTimeField 时间范围字段
+func WithConnTimeField(field string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.TimeField = field
+	}
+}
+
+// WithConnFilters 过滤条件，多次设置时以最后一次为准
+func WithConnFilters(filters ...Filter) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Filters = filters
+	}
+}
+
+// WithConnFilterStruct 带filter标签的过滤结构体
+func WithConnFilterStruct(v interface{}) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.FilterStruct = v
+	}
+}
+
+// WithConnAllowedFields 允许过滤和排序的字段
+func WithConnAllowedFields(fields []string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.AllowedFields = fields
+	}
+}
+
+// WithConnSortFields 允许排序的字段，未设置时使用 AllowedFields 或模型中存在的字段
+func WithConnSortFields(fields []string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.SortFields = fields
+	}
+}
+
 // WithConnUpdateName 更新字段key
 func WithConnUpdateName(name string) ConnectionOption {
 	return func(o *ConnectionOptions) {
@@ -142,6 +236,35 @@ func WithConnUpdateName(name string) ConnectionOption {
 	}
 }
 
+// WithConnConflictKeys 冲突字段
+func WithConnConflictKeys(keys []string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.ConflictKeys = keys
+	}
+}
+
+// WithConnUpdateAll 冲突时未指定 Values 则更新除主键、冲突字段、创建时间和创建人外的所有字段，
+// 默认冲突时不更新
+func WithConnUpdateAll(b bool) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.UpdateAll = b
+	}
+}
+
+// WithConnUpdateExcept 冲突时不更新的字段
+func WithConnUpdateExcept(fields []string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.UpdateExcept = fields
+	}
+}
+
+// WithConnBatchSize 批量写入每批数量
+func WithConnBatchSize(size int) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.BatchSize = size
+	}
+}
+
 // WithConnValues 更新的value
 func WithConnValues(v []string) ConnectionOption {
 	return func(o *ConnectionOptions) {
@@ -149,6 +272,76 @@ func WithConnValues(v []string) ConnectionOption {
 	}
 }
 
+// WithConnChanges 更新内容
+func WithConnChanges(changes map[string]interface{}) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Changes = changes
+	}
+}
+
+// WithConnOmitFields 更新时不写入的字段，ExcludeFields 只作用于查询条件
+func WithConnOmitFields(fields []string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.OmitFields = fields
+	}
+}
+
+// WithConnOperator 操作人
+func WithConnOperator(operator string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Operator = operator
+	}
+}
+
+// WithConnVersionField 乐观锁版本字段
+func WithConnVersionField(field string) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.VersionField = field
+	}
+}
+
+// WithConnVersion 乐观锁当前版本
+func WithConnVersion(version int64) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Version = &version
+	}
+}
+
+// WithConnRowsAffected 影响行数
+func WithConnRowsAffected(rows *int64) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.RowsAffected = rows
+	}
+}
+
+// WithConnPrimary 强制查询主库
+func WithConnPrimary(b bool) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Primary = b
+	}
+}
+
+// WithConnUnscoped 忽略软删除
+func WithConnUnscoped(b bool) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Unscoped = b
+	}
+}
+
+// WithConnCache 查询缓存，Query/First 的结果按表版本缓存，Create/Update/Delete 时失效
+func WithConnCache(pool cache.CachePool) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.Cache = pool
+	}
+}
+
+// WithConnCacheTTL 查询缓存过期时间，默认1分钟
+func WithConnCacheTTL(ttl time.Duration) ConnectionOption {
+	return func(o *ConnectionOptions) {
+		o.CacheTTL = ttl
+	}
+}
+
 // WithConnDebug 更新的value
 func WithConnDebug(b bool) ConnectionOption {
 	return func(o *ConnectionOptions) {
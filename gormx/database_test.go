@@ -0,0 +1,124 @@
+package gormx
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type testBook struct {
+	ID        int64  `json:"id" gorm:"primaryKey"`
+	Title     string `json:"title"`
+	Pages     int    `json:"pages"`
+	Note      string `json:"note"`
+	Version   int64  `json:"version"`
+	Sort      int    `json:"sort"`
+	UpdatedBy string `json:"updated_by"`
+}
+
+func TestDatabaseUpdate(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "update.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testBook{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	if err := db.Create(&testBook{ID: 1, Title: "draft", Pages: 10, Note: "keep", Version: 1}).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	load := func() testBook {
+		var doc testBook
+		if err := db.First(&doc, 1).Error; err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		return doc
+	}
+	update := func(model interface{}) *Database {
+		return NewDatabase(WithConnPool(db), WithConnDbModel(model),
+			WithConnConditions(map[string]interface{}{"id": 1}))
+	}
+
+	// 字段掩码允许写入零值，掩码外的字段不更新
+	var rows int64
+	err = update(&testBook{ID: 1, Title: "final", Pages: 0, Note: "ignored", Version: 1}).
+		SetValues([]string{"Title", "pages"}).
+		SetVersionField("version").
+		SetOperator("alice").
+		SetRowsAffected(&rows).
+		Update()
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if doc := load(); rows != 1 || doc.Title != "final" || doc.Pages != 0 || doc.Note != "keep" ||
+		doc.Version != 2 || doc.UpdatedBy != "alice" {
+		t.Errorf("unexpected document after masked update: %d %+v", rows, doc)
+	}
+
+	// 版本不一致
+	err = update(&testBook{}).SetChanges(map[string]interface{}{"title": "stale"}).
+		SetVersionField("version").SetVersion(1).SetRowsAffected(&rows).Update()
+	if !errors.Is(err, ErrStaleVersion) || rows != 0 {
+		t.Errorf("expected ErrStaleVersion, got %v (%d rows)", err, rows)
+	}
+	if doc := load(); doc.Title != "final" || doc.Version != 2 {
+		t.Errorf("expected stale update to be skipped, got %+v", doc)
+	}
+
+	// OmitFields 中的字段不更新
+	err = update(&testBook{}).SetChanges(map[string]interface{}{"title": "omitted", "note": "changed"}).
+		SetOmitFields([]string{"title"}).Update()
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if doc := load(); doc.Title != "final" || doc.Note != "changed" {
+		t.Errorf("expected title to be omitted, got %+v", doc)
+	}
+
+	// 默认 ExcludeFields 只作用于查询条件，sort 等同名字段可以更新
+	if err := update(&testBook{}).SetChanges(map[string]interface{}{"sort": 5}).Update(); err != nil {
+		t.Fatalf("Update sort failed: %v", err)
+	}
+	if doc := load(); doc.Sort != 5 {
+		t.Errorf("expected sort to be updated, got %+v", doc)
+	}
+
+	// 未设置掩码时只更新非零字段
+	if err := update(&testBook{Pages: 20}).Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if doc := load(); doc.Pages != 20 || doc.Title != "final" {
+		t.Errorf("expected only non-zero fields to be updated, got %+v", doc)
+	}
+
+	if err := update(&testBook{}).Update(); err == nil {
+		t.Error("expected error when there is nothing to update")
+	}
+}
+
+func TestDatabaseUpdateSQL(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "update_sql.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	dryRun := pool.GetConn().Session(&gorm.Session{DryRun: true})
+
+	var sql string
+	dryRun.Callback().Update().After("gorm:update").Register("test:sql", func(db *gorm.DB) {
+		sql = db.Statement.SQL.String()
+	})
+	err = NewDatabase(WithConnPool(dryRun), WithConnDbModel(&testBook{}),
+		WithConnConditions(map[string]interface{}{"id": 1}),
+		WithConnChanges(map[string]interface{}{"title": "x"}),
+		WithConnVersionField("version"), WithConnVersion(3)).Update()
+	if err != nil && !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("Update failed: %v", err)
+	}
+	want := "UPDATE `test_books` SET `title`=?,`version`=`version` + 1 WHERE `id` = ? AND `version` = ?"
+	if sql != want {
+		t.Errorf("expected %s, got %s", want, sql)
+	}
+}
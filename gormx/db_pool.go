@@ -1,17 +1,27 @@
 package gormx
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
+// maxRetryBackoff 连接重试的最大间隔
+const maxRetryBackoff = 30 * time.Second
+
 // DBPool db连接池
 type DBPool struct {
-	opts Options
-	DB   *gorm.DB
+	opts     Options
+	resolver *dbresolver.DBResolver
+	tenantMu sync.Mutex
+	tenants  map[string]*DBPool
+	DB       *gorm.DB
 }
 
 // NewDBPool get db pool
@@ -20,18 +30,22 @@ func NewDBPool(opts ...Option) (*DBPool, error) {
 
 	// use your own DB link if you set it up yourself
 	if options.db != nil {
-		return &DBPool{opts: options, DB: options.db}, nil
-	}
-
-	conn := fmt.Sprintf("%v:%v@(%v:%v)/%v?charset=utf8&parseTime=True&loc=Local",
-		options.user,
-		options.password,
-		options.uri,
-		options.port,
-		options.name,
-	)
-	cf := new(gorm.Config)
-	pool, err := gorm.Open(mysql.Open(conn), cf)
+		resolver, err := setupPool(options.db, options)
+		if err != nil {
+			return nil, err
+		}
+		return &DBPool{opts: options, resolver: resolver, DB: options.db}, nil
+	}
+
+	dial, err := dialector(options)
+	if err != nil {
+		return nil, err
+	}
+	cf := &gorm.Config{Logger: options.logger}
+	if cf.Logger == nil {
+		cf.Logger = NewQueryLogger()
+	}
+	pool, err := open(dial, cf, options)
 	if err != nil {
 		return nil, err
 	}
@@ -44,13 +58,127 @@ func NewDBPool(opts ...Option) (*DBPool, error) {
 	db.SetMaxOpenConns(options.MaxOpenConn)
 	db.SetMaxIdleConns(options.MaxIdleConn)
 	db.SetConnMaxLifetime(options.ConnMaxLifetime)
-	return &DBPool{opts: options, DB: pool}, nil
+
+	resolver, err := setupPool(pool, options)
+	if err != nil {
+		return nil, err
+	}
+	return &DBPool{opts: options, resolver: resolver, DB: pool}, nil
 }
 
-// GetConn get conn
+// open 连接数据库，失败时按 Retry 设置的次数重试，重试间隔每次翻倍，RetryContext 取消时停止重试
+func open(dial gorm.Dialector, cf *gorm.Config, o Options) (*gorm.DB, error) {
+	backoff := o.retryBackoff
+	for attempt := 0; ; attempt++ {
+		pool, err := gorm.Open(dial, cf)
+		if err == nil || attempt >= o.retries {
+			return pool, err
+		}
+		if pool != nil {
+			if db, dbErr := pool.DB(); dbErr == nil {
+				_ = db.Close()
+			}
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-o.retryCtx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%v: %w", err, o.retryCtx.Err())
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// setupPool 注册从库和插件
+func setupPool(pool *gorm.DB, o Options) (*dbresolver.DBResolver, error) {
+	resolver, err := registerReplicas(pool, o)
+	if err != nil {
+		return nil, err
+	}
+	for _, plugin := range o.plugins {
+		if err := pool.Use(plugin); err != nil {
+			return nil, err
+		}
+	}
+	return resolver, nil
+}
+
+// GetConn get conn，未初始化时退出进程，需要返回错误时使用 Conn
 func (c *DBPool) GetConn() *gorm.DB {
 	if c.DB == nil {
 		log.Fatal("database client not init")
 	}
 	return c.DB
 }
+
+// Conn 获取连接，未初始化时返回 ErrNotInitialized
+func (c *DBPool) Conn() (*gorm.DB, error) {
+	if c == nil || c.DB == nil {
+		return nil, ErrNotInitialized
+	}
+	return c.DB, nil
+}
+
+// Ping 检查主库和所有从库的连接，可用于健康检查
+func (c *DBPool) Ping(ctx context.Context) error {
+	return c.eachDB(func(db *sql.DB) error {
+		return db.PingContext(ctx)
+	})
+}
+
+// Stats 获取主库连接池统计
+func (c *DBPool) Stats() sql.DBStats {
+	if c == nil || c.DB == nil {
+		return sql.DBStats{}
+	}
+	db, err := c.DB.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return db.Stats()
+}
+
+// Close 关闭主库、所有从库和租户连接池的连接
+func (c *DBPool) Close() error {
+	err := c.eachDB(func(db *sql.DB) error {
+		return db.Close()
+	})
+	if c == nil {
+		return err
+	}
+
+	c.tenantMu.Lock()
+	defer c.tenantMu.Unlock()
+	for _, pool := range c.tenants {
+		if closeErr := pool.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	c.tenants = nil
+	return err
+}
+
+// eachDB 依次处理主库和从库的 *sql.DB
+func (c *DBPool) eachDB(fn func(*sql.DB) error) error {
+	if c == nil || c.DB == nil {
+		return ErrNotInitialized
+	}
+	if c.resolver != nil {
+		return c.resolver.Call(func(pool gorm.ConnPool) error {
+			if db, ok := pool.(*sql.DB); ok {
+				return fn(db)
+			}
+			return nil
+		})
+	}
+
+	db, err := c.DB.DB()
+	if err != nil {
+		return err
+	}
+	return fn(db)
+}
@@ -1,9 +1,19 @@
 package gormx
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 数据库驱动
+const (
+	DriverMySQL     = "mysql"     // MySQL
+	DriverPostgres  = "postgres"  // PostgreSQL
+	DriverSQLite    = "sqlite"    // SQLite，name 为文件路径或 :memory:
+	DriverSQLServer = "sqlserver" // SQL Server
 )
 
 // Option ...
@@ -13,18 +23,36 @@ type Option func(*Options)
 type Options struct {
 	db *gorm.DB
 
+	driver          string // 驱动，默认 mysql
+	dsn             string // 自定义DSN，设置后忽略连接参数
 	uri             string
 	port            string
 	name            string
 	user            string
 	password        string
-	MaxOpenConn     int           // 最大连接
-	MaxIdleConn     int           // 最大空闲连接
-	ConnMaxLifetime time.Duration // 最大空闲时间
+	charset         string                       // 字符集，仅 mysql
+	timeZone        string                       // 时区，仅 mysql 和 postgres
+	tls             string                       // TLS配置，mysql 的 tls、postgres 的 sslmode、sqlserver 的 encrypt
+	params          map[string]string            // 额外连接参数
+	replicas        []replica                    // 从库
+	replicaPolicy   string                       // 从库选择策略
+	logger          logger.Interface             // SQL日志，默认 NewQueryLogger()
+	plugins         []gorm.Plugin                // 插件，如 NewQueryMetrics()、NewAuditPlugin()
+	retries         int                          // 启动时连接失败的重试次数
+	retryBackoff    time.Duration                // 首次重试间隔，之后每次翻倍
+	retryCtx        context.Context              // 取消后停止重试
+	tenantDatabase  func(tenant string) []Option // 按租户分库时每个租户的连接配置
+	MaxOpenConn     int                          // 最大连接
+	MaxIdleConn     int                          // 最大空闲连接
+	ConnMaxLifetime time.Duration                // 最大空闲时间
 }
 
 func newOptions(opts ...Option) Options {
-	opt := *new(Options)
+	opt := Options{
+		driver:   DriverMySQL,
+		params:   make(map[string]string),
+		retryCtx: context.Background(),
+	}
 	for _, o := range opts {
 		o(&opt)
 	}
@@ -38,6 +66,20 @@ func DB(db *gorm.DB) Option {
 	}
 }
 
+// Driver 设置数据库驱动
+func Driver(driver string) Option {
+	return func(o *Options) {
+		o.driver = driver
+	}
+}
+
+// DSN 设置自定义DSN，优先于 Uri/Port/Name 等连接参数
+func DSN(dsn string) Option {
+	return func(o *Options) {
+		o.dsn = dsn
+	}
+}
+
 // Uri ...
 func Uri(uri string) Option {
 	return func(o *Options) {
@@ -73,6 +115,92 @@ func PassWord(pwd string) Option {
 	}
 }
 
+// Charset 设置字符集，如 utf8mb4，仅 mysql 有效
+func Charset(charset string) Option {
+	return func(o *Options) {
+		o.charset = charset
+	}
+}
+
+// TimeZone 设置时区，如 Asia/Shanghai，仅 mysql 和 postgres 有效
+func TimeZone(tz string) Option {
+	return func(o *Options) {
+		o.timeZone = tz
+	}
+}
+
+// TLS 设置TLS，mysql 对应 tls 参数(true/skip-verify/自定义配置名)，
+// postgres 对应 sslmode(disable/require/verify-full)，sqlserver 对应 encrypt(true/false/disable)
+func TLS(tls string) Option {
+	return func(o *Options) {
+		o.tls = tls
+	}
+}
+
+// Param 添加额外连接参数
+func Param(key, value string) Option {
+	return func(o *Options) {
+		o.params[key] = value
+	}
+}
+
+// Replica 添加从库，沿用主库的驱动、账号和库名
+func Replica(uri, port string) Option {
+	return func(o *Options) {
+		o.replicas = append(o.replicas, replica{uri: uri, port: port})
+	}
+}
+
+// ReplicaDSN 使用自定义DSN添加从库
+func ReplicaDSN(dsn string) Option {
+	return func(o *Options) {
+		o.replicas = append(o.replicas, replica{dsn: dsn})
+	}
+}
+
+// ReplicaPolicy 设置从库选择策略，ReplicaRoundRobin(默认) 或 ReplicaLeastConn
+func ReplicaPolicy(policy string) Option {
+	return func(o *Options) {
+		o.replicaPolicy = policy
+	}
+}
+
+// Logger 设置SQL日志，默认使用 NewQueryLogger() 通过 logx 输出错误和慢查询
+func Logger(l logger.Interface) Option {
+	return func(o *Options) {
+		o.logger = l
+	}
+}
+
+// Plugin 添加 gorm 插件
+func Plugin(plugins ...gorm.Plugin) Option {
+	return func(o *Options) {
+		o.plugins = append(o.plugins, plugins...)
+	}
+}
+
+// Retry 设置启动时连接失败的重试次数和首次重试间隔，间隔每次翻倍，最长30秒
+func Retry(attempts int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.retries = attempts
+		o.retryBackoff = backoff
+	}
+}
+
+// RetryContext 设置重试使用的上下文，取消后停止等待并返回，如收到退出信号时
+func RetryContext(ctx context.Context) Option {
+	return func(o *Options) {
+		o.retryCtx = ctx
+	}
+}
+
+// TenantDatabase 按租户分库，fn 返回租户连接池的配置，通过 DBPool.Tenant 获取租户连接池
+func TenantDatabase(fn func(tenant string) []Option) Option {
+	return func(o *Options) {
+		o.tenantDatabase = fn
+	}
+}
+
 // MaxOpenConn 最大连接数
 func MaxOpenConn(n int) Option {
 	return func(o *Options) {
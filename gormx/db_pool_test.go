@@ -0,0 +1,72 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+func TestDBPoolHealth(t *testing.T) {
+	if _, err := (&DBPool{}).Conn(); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("expected ErrNotInitialized, got %v", err)
+	}
+
+	dir := t.TempDir()
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(dir, "primary.db")),
+		ReplicaDSN(filepath.Join(dir, "replica.db")), MaxIdleConn(2))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := pool.Ping(ctx); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+	if stats := pool.Stats(); stats.OpenConnections == 0 {
+		t.Errorf("expected open connections, got %+v", stats)
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := pool.Ping(ctx); err == nil {
+		t.Error("expected Ping to fail after Close")
+	}
+}
+
+func TestDBPoolRetry(t *testing.T) {
+	start := time.Now()
+	_, err := NewDBPool(
+		Driver(DriverSQLite),
+		Name(filepath.Join(t.TempDir(), "missing", "app.db")),
+		Logger(logger.Discard),
+		Retry(2, 10*time.Millisecond),
+	)
+	if err == nil {
+		t.Fatal("expected error for unreachable database")
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected 2 retries with backoff, returned after %v", elapsed)
+	}
+
+	// 取消后停止等待
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = NewDBPool(
+		Driver(DriverSQLite),
+		Name(filepath.Join(t.TempDir(), "missing", "app.db")),
+		Logger(logger.Discard),
+		Retry(5, time.Second),
+		RetryContext(ctx),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected retry to stop on cancel, returned after %v", elapsed)
+	}
+}
@@ -0,0 +1,24 @@
+package gormx
+
+import "errors"
+
+var (
+	// ErrStaleVersion 乐观锁版本不一致，记录已被其他请求修改
+	ErrStaleVersion = errors.New("stale version: record has been modified")
+	// ErrMissingPrimaryKey 按主键更新时主键为空
+	ErrMissingPrimaryKey = errors.New("missing primary key")
+	// ErrFieldNotAllowed 字段不在白名单中
+	ErrFieldNotAllowed = errors.New("field not allowed")
+	// ErrNotInitialized 连接池未初始化
+	ErrNotInitialized = errors.New("database client not init")
+	// ErrTenantRequired 严格租户模式下上下文中没有租户
+	ErrTenantRequired = errors.New("tenant required")
+	// ErrTenantMismatch 写入的租户字段与上下文中的租户不一致
+	ErrTenantMismatch = errors.New("tenant mismatch")
+	// ErrInvalidTenant 租户名称不是合法的标识符，不能用于schema路由
+	ErrInvalidTenant = errors.New("invalid tenant")
+	// ErrInvalidSort 排序规则格式错误
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidCursor 游标无法解码或与排序字段不匹配
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
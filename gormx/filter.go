@@ -0,0 +1,350 @@
+package gormx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FilterTag 结构体过滤标签，格式: `filter:"column,op"`，多个字段用 | 分隔表示 OR，如 `filter:"name|nickname,like"`
+const FilterTag = "filter"
+
+// Operator 过滤操作符
+type Operator string
+
+const (
+	OpEq      Operator = "eq"       // 等于
+	OpNe      Operator = "ne"       // 不等于
+	OpGt      Operator = "gt"       // 大于
+	OpGte     Operator = "gte"      // 大于等于
+	OpLt      Operator = "lt"       // 小于
+	OpLte     Operator = "lte"      // 小于等于
+	OpIn      Operator = "in"       // 包含
+	OpNotIn   Operator = "not_in"   // 不包含
+	OpLike    Operator = "like"     // 模糊匹配
+	OpPrefix  Operator = "prefix"   // 前缀匹配
+	OpSuffix  Operator = "suffix"   // 后缀匹配
+	OpIsNull  Operator = "null"     // 为空
+	OpNotNull Operator = "not_null" // 不为空
+)
+
+// operators 支持的操作符
+var operators = map[Operator]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpIn: true, OpNotIn: true, OpLike: true, OpPrefix: true, OpSuffix: true,
+	OpIsNull: true, OpNotNull: true,
+}
+
+// Filter 过滤条件，Field 为空时表示由 Filters 组成的 AND/OR 分组
+type Filter struct {
+	Field   string      // 字段
+	Op      Operator    // 操作符
+	Value   interface{} // 值
+	Or      bool        // 分组内条件是否使用 OR 连接
+	Filters []Filter    // 分组子条件
+}
+
+// Eq 等于
+func Eq(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpEq, Value: value}
+}
+
+// Ne 不等于
+func Ne(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpNe, Value: value}
+}
+
+// Gt 大于
+func Gt(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpGt, Value: value}
+}
+
+// Gte 大于等于
+func Gte(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpGte, Value: value}
+}
+
+// Lt 小于
+func Lt(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpLt, Value: value}
+}
+
+// Lte 小于等于
+func Lte(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpLte, Value: value}
+}
+
+// In 包含，value 为切片
+func In(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpIn, Value: value}
+}
+
+// NotIn 不包含，value 为切片
+func NotIn(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpNotIn, Value: value}
+}
+
+// Like 模糊匹配
+func Like(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpLike, Value: value}
+}
+
+// Prefix 前缀匹配
+func Prefix(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpPrefix, Value: value}
+}
+
+// Suffix 后缀匹配
+func Suffix(field string, value interface{}) Filter {
+	return Filter{Field: field, Op: OpSuffix, Value: value}
+}
+
+// IsNull 为空
+func IsNull(field string) Filter {
+	return Filter{Field: field, Op: OpIsNull}
+}
+
+// NotNull 不为空
+func NotNull(field string) Filter {
+	return Filter{Field: field, Op: OpNotNull}
+}
+
+// And 使用 AND 连接的条件分组
+func And(filters ...Filter) Filter {
+	return Filter{Filters: filters}
+}
+
+// Or 使用 OR 连接的条件分组
+func Or(filters ...Filter) Filter {
+	return Filter{Or: true, Filters: filters}
+}
+
+// build 生成查询表达式，resolve 负责字段白名单校验并返回列名
+func (f Filter) build(resolve func(string) (clause.Column, error)) (clause.Expression, error) {
+	if f.Field == "" {
+		return f.buildGroup(resolve)
+	}
+
+	column, err := resolve(f.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.Op {
+	case OpEq, "":
+		return clause.Eq{Column: column, Value: f.Value}, nil
+	case OpNe:
+		return clause.Neq{Column: column, Value: f.Value}, nil
+	case OpGt:
+		return clause.Gt{Column: column, Value: f.Value}, nil
+	case OpGte:
+		return clause.Gte{Column: column, Value: f.Value}, nil
+	case OpLt:
+		return clause.Lt{Column: column, Value: f.Value}, nil
+	case OpLte:
+		return clause.Lte{Column: column, Value: f.Value}, nil
+	case OpIn:
+		return clause.IN{Column: column, Values: toValues(f.Value)}, nil
+	case OpNotIn:
+		values := toValues(f.Value)
+		if len(values) == 0 {
+			// 空集合不排除任何记录，跳过条件
+			return nil, nil
+		}
+		return clause.Not(clause.IN{Column: column, Values: values}), nil
+	case OpLike:
+		return clause.Like{Column: column, Value: fmt.Sprintf("%%%v%%", f.Value)}, nil
+	case OpPrefix:
+		return clause.Like{Column: column, Value: fmt.Sprintf("%v%%", f.Value)}, nil
+	case OpSuffix:
+		return clause.Like{Column: column, Value: fmt.Sprintf("%%%v", f.Value)}, nil
+	case OpIsNull:
+		return clause.Eq{Column: column, Value: nil}, nil
+	case OpNotNull:
+		return clause.Neq{Column: column, Value: nil}, nil
+	default:
+		return nil, fmt.Errorf("unsupported filter operator: %s", f.Op)
+	}
+}
+
+// buildGroup 生成分组表达式，空分组返回 nil
+func (f Filter) buildGroup(resolve func(string) (clause.Column, error)) (clause.Expression, error) {
+	exprs := make([]clause.Expression, 0, len(f.Filters))
+	for _, child := range f.Filters {
+		expr, err := child.build(resolve)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			exprs = append(exprs, expr)
+		}
+	}
+
+	switch {
+	case len(exprs) == 0:
+		return nil, nil
+	case f.Or:
+		return clause.Or(exprs...), nil
+	default:
+		return clause.And(exprs...), nil
+	}
+}
+
+// toValues 将切片转换为 []interface{}
+func toValues(value interface{}) []interface{} {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{value}
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values
+}
+
+// ParseFilters 根据结构体的 filter 标签生成过滤条件，零值字段会被忽略
+func ParseFilters(v interface{}) ([]Filter, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("filter source must be a struct")
+	}
+
+	var filters []Filter
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field, value := rt.Field(i), rv.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup(FilterTag)
+		if !ok {
+			// 展开匿名结构体，如嵌入的查询基类
+			if field.Anonymous {
+				if embedded := reflect.Indirect(value); embedded.Kind() == reflect.Struct {
+					children, err := ParseFilters(embedded.Interface())
+					if err != nil {
+						return nil, err
+					}
+					filters = append(filters, children...)
+				}
+			}
+			continue
+		}
+		if tag == "-" || value.IsZero() {
+			continue
+		}
+
+		filter, err := parseFilterTag(tag, reflect.Indirect(value).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter tag on %s: %w", field.Name, err)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// parseFilterTag 解析 column,op 格式的标签
+func parseFilterTag(tag string, value interface{}) (Filter, error) {
+	columns, op, _ := strings.Cut(tag, ",")
+	operator := Operator(strings.TrimSpace(op))
+	if operator == "" {
+		operator = OpEq
+	}
+	if !operators[operator] {
+		return Filter{}, fmt.Errorf("unsupported filter operator: %s", operator)
+	}
+
+	names := strings.Split(columns, "|")
+	group := Filter{Or: true}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return Filter{}, fmt.Errorf("empty column name")
+		}
+		group.Filters = append(group.Filters, Filter{Field: name, Op: operator, Value: value})
+	}
+	if len(group.Filters) == 1 {
+		return group.Filters[0], nil
+	}
+	return group, nil
+}
+
+// columnResolver 返回字段白名单校验函数。
+// 设置了 AllowedFields 时只允许列表中的字段，否则只允许模型中存在的字段
+func (d *Database) columnResolver(query *gorm.DB) func(string) (string, error) {
+	return d.whitelistResolver(query, d.opts.AllowedFields)
+}
+
+// sortResolver 返回排序字段白名单校验函数，设置了 SortFields 时只允许列表中的字段，
+// 否则与 columnResolver 相同
+func (d *Database) sortResolver(query *gorm.DB) func(string) (string, error) {
+	if len(d.opts.SortFields) > 0 {
+		return d.whitelistResolver(query, d.opts.SortFields)
+	}
+	return d.columnResolver(query)
+}
+
+// whitelistResolver fields 非空时只允许列表中的字段，否则只允许模型中存在的字段
+func (d *Database) whitelistResolver(query *gorm.DB, fields []string) func(string) (string, error) {
+	if len(fields) > 0 {
+		allowed := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			allowed[field] = true
+		}
+		return func(name string) (string, error) {
+			if !allowed[name] {
+				return "", fmt.Errorf("%w: %s", ErrFieldNotAllowed, name)
+			}
+			return name, nil
+		}
+	}
+
+	return func(name string) (string, error) {
+		if query.Statement.Schema == nil {
+			if err := query.Statement.Parse(d.opts.DbModel); err != nil {
+				return "", fmt.Errorf("failed to parse model: %w", err)
+			}
+		}
+		if field := query.Statement.Schema.LookUpField(name); field != nil && field.DBName != "" {
+			return field.DBName, nil
+		}
+		return "", fmt.Errorf("%w: %s", ErrFieldNotAllowed, name)
+	}
+}
+
+// resolveColumn 将字段名解析结果转换为 clause.Column
+func resolveColumn(resolve func(string) (string, error)) func(string) (clause.Column, error) {
+	return func(name string) (clause.Column, error) {
+		name, err := resolve(name)
+		return clause.Column{Name: name}, err
+	}
+}
+
+// applyFilters 追加过滤条件
+func (d *Database) applyFilters(query *gorm.DB) (*gorm.DB, error) {
+	filters := d.opts.Filters
+	if d.opts.FilterStruct != nil {
+		parsed, err := ParseFilters(d.opts.FilterStruct)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(append([]Filter(nil), filters...), parsed...)
+	}
+	if len(filters) == 0 {
+		return query, nil
+	}
+
+	expr, err := And(filters...).build(resolveColumn(d.columnResolver(query)))
+	if err != nil {
+		return nil, err
+	}
+	if expr != nil {
+		query = query.Where(expr)
+	}
+	return query, nil
+}
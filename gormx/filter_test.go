@@ -0,0 +1,132 @@
+package gormx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type testUser struct {
+	ID    int64  `json:"id" gorm:"primaryKey"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+type testUserQuery struct {
+	Keyword string  `json:"keyword" filter:"name|email,like"`
+	MinAge  *int    `json:"min_age" filter:"age,gte"`
+	IDs     []int64 `json:"ids" filter:"id,not_in"`
+	Page    int     `json:"page"`
+}
+
+// dryRunDB 创建不连接数据库的dry run连接，用于检查生成的SQL
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pwd@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open dry run db: %v", err)
+	}
+	return db
+}
+
+// querySQL 返回Database生成的查询SQL
+func querySQL(t *testing.T, d *Database) (string, error) {
+	t.Helper()
+	query, err := d.prepareQuery()
+	if err != nil {
+		return "", err
+	}
+	query = d.applyPagination(query)
+	if query, err = d.applyOrder(query); err != nil {
+		return "", err
+	}
+	var users []testUser
+	return query.Find(&users).Statement.SQL.String(), nil
+}
+
+func TestParseFilters(t *testing.T) {
+	age := 18
+	filters, err := ParseFilters(&testUserQuery{Keyword: "tom", MinAge: &age})
+	if err != nil {
+		t.Fatalf("ParseFilters failed: %v", err)
+	}
+	if len(filters) != 2 {
+		t.Fatalf("expected 2 filters, got %d", len(filters))
+	}
+	if !filters[0].Or || len(filters[0].Filters) != 2 {
+		t.Errorf("expected OR group over name and email, got %+v", filters[0])
+	}
+	if filters[1].Field != "age" || filters[1].Op != OpGte || filters[1].Value != 18 {
+		t.Errorf("unexpected age filter: %+v", filters[1])
+	}
+
+	if _, err := ParseFilters(&struct {
+		Name string `filter:"name,unknown"`
+	}{Name: "x"}); err == nil {
+		t.Error("expected error for unknown operator")
+	}
+}
+
+func TestDatabaseFilters(t *testing.T) {
+	db := dryRunDB(t)
+	age := 18
+	sql, err := querySQL(t, NewDatabase(
+		WithConnPool(db),
+		WithConnDbModel(&testUser{}),
+		WithConnFilterStruct(&testUserQuery{Keyword: "tom", MinAge: &age}),
+		WithConnFilters(Or(IsNull("email"), Prefix("name", "t")), NotIn("id", []int{1, 2})),
+	))
+	if err != nil {
+		t.Fatalf("prepareQuery failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"(`email` IS NULL OR `name` LIKE ?)",
+		"`id` NOT IN (?,?)",
+		"(`name` LIKE ? OR `email` LIKE ?)",
+		"`age` >= ?",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in %s", want, sql)
+		}
+	}
+}
+
+func TestDatabaseFiltersEmptyNotIn(t *testing.T) {
+	db := dryRunDB(t)
+	sql, err := querySQL(t, NewDatabase(
+		WithConnPool(db),
+		WithConnDbModel(&testUser{}),
+		WithConnFilters(Eq("name", "ignored")),
+		WithConnFilters(NotIn("id", []int{}), Eq("age", 1)),
+	))
+	if err != nil {
+		t.Fatalf("prepareQuery failed: %v", err)
+	}
+	if strings.Contains(sql, "`id`") || strings.Contains(sql, "`name`") || !strings.Contains(sql, "`age` = ?") {
+		t.Errorf("expected empty NOT IN skipped and filters overwritten, got %s", sql)
+	}
+}
+
+func TestDatabaseFiltersWhitelist(t *testing.T) {
+	db := dryRunDB(t)
+
+	_, err := querySQL(t, NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}),
+		WithConnFilters(Eq("name = 1 OR 1", 1))))
+	if !errors.Is(err, ErrFieldNotAllowed) {
+		t.Errorf("expected ErrFieldNotAllowed for unknown column, got %v", err)
+	}
+
+	_, err = querySQL(t, NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}),
+		WithConnAllowedFields([]string{"name"}), WithConnFilters(Eq("age", 1))))
+	if !errors.Is(err, ErrFieldNotAllowed) {
+		t.Errorf("expected ErrFieldNotAllowed outside allowed fields, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package gormxtest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	refKey    = "_ref"  // 记录的引用名
+	refPrefix = "$ref:" // 引用其他记录的值，"$ref:name" 为主键，"$ref:name.field" 为指定字段
+)
+
+// load 加载fixture文件。文件格式为 表名 -> 记录列表，JSON 作为 YAML 的子集解析，
+// 表和记录按文件中的顺序写入，引用只能指向之前写入的记录：
+//
+//	users:
+//	  - _ref: tom
+//	    name: tom
+//	orders:
+//	  - user_id: $ref:tom
+//	    email: $ref:tom.email
+func (db *DB) load(conn *gorm.DB, file fixtureFile) error {
+	data, err := readFixture(file)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %s: %w", file.path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse fixture %s: %w", file.path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("fixture %s: expected a mapping of table to records", file.path)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		table := root.Content[i].Value
+		var records []map[string]interface{}
+		if err := root.Content[i+1].Decode(&records); err != nil {
+			return fmt.Errorf("fixture %s: table %s: %w", file.path, table, err)
+		}
+		for j, record := range records {
+			if err := db.insert(conn, table, record); err != nil {
+				return fmt.Errorf("fixture %s: table %s record %d: %w", file.path, table, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readFixture 读取fixture文件
+func readFixture(file fixtureFile) ([]byte, error) {
+	if file.fsys != nil {
+		return fs.ReadFile(file.fsys, file.path)
+	}
+	return os.ReadFile(file.path)
+}
+
+// insert 将记录转换为模型并写入，写入前检查 _ref 是否重复，写入后按 _ref 保存
+func (db *DB) insert(conn *gorm.DB, table string, record map[string]interface{}) error {
+	modelType, ok := db.models[table]
+	if !ok {
+		return fmt.Errorf("no model registered for table %s", table)
+	}
+	model := reflect.New(modelType)
+	stmt := &gorm.Statement{DB: conn}
+	if err := stmt.Parse(model.Interface()); err != nil {
+		return err
+	}
+
+	ref, _ := record[refKey].(string)
+	delete(record, refKey)
+	if _, ok := db.refs[ref]; ok && ref != "" {
+		return fmt.Errorf("duplicate ref %s", ref)
+	}
+	for name, value := range record {
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			return fmt.Errorf("unknown field %s", name)
+		}
+		value, err := db.resolve(value)
+		if err != nil {
+			return err
+		}
+		if err := field.Set(conn.Statement.Context, model.Elem(), value); err != nil {
+			return fmt.Errorf("failed to set field %s: %w", name, err)
+		}
+	}
+
+	if err := conn.Create(model.Interface()).Error; err != nil {
+		return err
+	}
+	if ref != "" {
+		db.refs[ref] = model.Interface()
+	}
+	return nil
+}
+
+// resolve 解析 $ref: 引用
+func (db *DB) resolve(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, refPrefix) {
+		return value, nil
+	}
+
+	name, fieldName, _ := strings.Cut(strings.TrimPrefix(s, refPrefix), ".")
+	target, ok := db.refs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ref %s", name)
+	}
+	stmt := &gorm.Statement{DB: db.GetConn()}
+	if err := stmt.Parse(target); err != nil {
+		return nil, err
+	}
+	sch := stmt.Schema
+
+	var field *schema.Field
+	if fieldName == "" {
+		field = sch.PrioritizedPrimaryField
+	} else {
+		field = sch.LookUpField(fieldName)
+	}
+	if field == nil {
+		return nil, fmt.Errorf("unknown ref field %s", s)
+	}
+	v, _ := field.ValueOf(context.Background(), reflect.ValueOf(target).Elem())
+	return v, nil
+}
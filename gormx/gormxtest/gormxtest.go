@@ -0,0 +1,105 @@
+// Package gormxtest gormx 测试工具：创建内存 SQLite 连接池、自动迁移模型、加载 YAML/JSON fixtures，
+// 并为每个测试开启结束时回滚的事务，离线测试 Repository 和 Database
+package gormxtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hchicken/pkg-go/gormx"
+	"gorm.io/gorm"
+)
+
+// seq 内存数据库序号，保证每个 DB 使用独立的数据库
+var seq int64
+
+// DB 测试数据库
+type DB struct {
+	*gormx.DBPool
+	models map[string]reflect.Type // 表名对应的模型类型
+	refs   map[string]interface{}  // fixture引用名对应的记录
+}
+
+// New 创建内存 SQLite 连接池，自动迁移模型并加载fixtures，测试结束时关闭连接池
+func New(t testing.TB, opts ...Option) *DB {
+	t.Helper()
+	options := newOptions(opts...)
+
+	// 共享缓存的内存数据库在最后一个连接关闭时销毁，保留一个空闲连接
+	name := fmt.Sprintf("file:gormxtest_%d?mode=memory&cache=shared", atomic.AddInt64(&seq, 1))
+	poolOpts := append([]gormx.Option{
+		gormx.Driver(gormx.DriverSQLite),
+		gormx.Name(name),
+		gormx.MaxIdleConn(1),
+	}, options.pool...)
+	pool, err := gormx.NewDBPool(poolOpts...)
+	if err != nil {
+		t.Fatalf("gormxtest: failed to create pool: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = pool.Close()
+	})
+
+	db := &DB{DBPool: pool, models: make(map[string]reflect.Type), refs: make(map[string]interface{})}
+	conn := pool.GetConn()
+	for _, model := range options.models {
+		if err := conn.AutoMigrate(model); err != nil {
+			t.Fatalf("gormxtest: failed to migrate %T: %v", model, err)
+		}
+		stmt := &gorm.Statement{DB: conn}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("gormxtest: failed to parse %T: %v", model, err)
+		}
+		db.models[stmt.Table] = stmt.Schema.ModelType
+	}
+
+	for _, file := range options.fixtures {
+		if err := db.load(conn, file); err != nil {
+			t.Fatalf("gormxtest: %v", err)
+		}
+	}
+	return db
+}
+
+// Begin 开启事务并在测试结束时回滚，返回携带事务的上下文。
+// 使用该上下文的 gormx.Database 和 gormx.Repository 在事务中执行，
+// 事务中 Load 的 _ref 引用在回滚时一并移除；
+// 事务持有写锁期间，不使用该上下文的查询会返回 database table is locked
+func (db *DB) Begin(t testing.TB) context.Context {
+	t.Helper()
+	tx := db.GetConn().Begin()
+	if tx.Error != nil {
+		t.Fatalf("gormxtest: failed to begin transaction: %v", tx.Error)
+	}
+	refs := make(map[string]interface{}, len(db.refs))
+	for name, ref := range db.refs {
+		refs[name] = ref
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+		db.refs = refs
+	})
+	return gormx.ContextWithTx(context.Background(), tx)
+}
+
+// Load 在 ctx 中加载fixture文件，ctx 来自 Begin 时随事务回滚
+func (db *DB) Load(ctx context.Context, paths ...string) error {
+	conn := db.GetConn()
+	if tx, ok := gormx.TxFromContext(ctx); ok {
+		conn = tx
+	}
+	for _, path := range paths {
+		if err := db.load(conn.WithContext(ctx), fixtureFile{path: path}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ref 获取fixture中 _ref 对应的记录，为模型指针
+func (db *DB) Ref(name string) interface{} {
+	return db.refs[name]
+}
@@ -0,0 +1,80 @@
+package gormxtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hchicken/pkg-go/gormx"
+)
+
+type testUser struct {
+	ID    int64  `json:"id" gorm:"primaryKey"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type testOrder struct {
+	ID     int64   `json:"id" gorm:"primaryKey"`
+	UserID int64   `json:"user_id"`
+	Email  string  `json:"email"`
+	Amount float64 `json:"amount"`
+}
+
+func TestFixtures(t *testing.T) {
+	db := New(t, Models(&testUser{}, &testOrder{}), Fixtures("testdata/fixtures.yaml"))
+
+	tom, ok := db.Ref("tom").(*testUser)
+	if !ok || tom.ID == 0 {
+		t.Fatalf("expected ref tom to be loaded, got %+v", db.Ref("tom"))
+	}
+	orders, total, err := gormx.NewRepository[testOrder](db.GetConn()).List(context.Background())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if total != 1 || orders[0].UserID != tom.ID || orders[0].Email != tom.Email || orders[0].Amount != 10.5 {
+		t.Errorf("expected order referencing tom, got %d %+v", total, orders)
+	}
+}
+
+func TestBegin(t *testing.T) {
+	db := New(t, Models(&testUser{}, &testOrder{}), Fixtures("testdata/fixtures.yaml"))
+	repo := gormx.NewRepository[testOrder](db.GetConn())
+
+	t.Run("write", func(t *testing.T) {
+		ctx := db.Begin(t)
+		if err := db.Load(ctx, "testdata/orders.json"); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if err := gormx.NewDatabase(gormx.WithConnPool(db.GetConn()), gormx.WithConnContext(ctx),
+			gormx.WithConnDbModel(&testOrder{}), gormx.WithConnConditions(map[string]interface{}{"amount": 10.5})).Delete(); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		_, total, err := repo.List(ctx)
+		if err != nil || total != 1 {
+			t.Errorf("expected 1 order in transaction, got %d, %v", total, err)
+		}
+	})
+
+	_, total, err := repo.List(context.Background())
+	if err != nil || total != 1 {
+		t.Errorf("expected transaction to be rolled back, got %d, %v", total, err)
+	}
+	if ref := db.Ref("big_order"); ref != nil {
+		t.Errorf("expected ref to be removed with the transaction, got %+v", ref)
+	}
+
+	t.Run("reload", func(t *testing.T) {
+		ctx := db.Begin(t)
+		if err := db.Load(ctx, "testdata/orders.json"); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if err := db.Load(ctx, "testdata/fixtures.yaml"); err == nil || !strings.Contains(err.Error(), "duplicate ref tom") {
+			t.Fatalf("expected duplicate ref error, got %v", err)
+		}
+		total, err := gormx.NewRepository[testUser](db.GetConn()).Count(ctx)
+		if err != nil || total != 2 {
+			t.Errorf("expected duplicate ref to be rejected before insert, got %d, %v", total, err)
+		}
+	})
+}
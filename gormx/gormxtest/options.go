@@ -0,0 +1,63 @@
+package gormxtest
+
+import (
+	"io/fs"
+
+	"github.com/hchicken/pkg-go/gormx"
+)
+
+// Option ...
+type Option func(*Options)
+
+// Options gormxtest options
+type Options struct {
+	models   []interface{}  // 自动迁移的模型
+	fixtures []fixtureFile  // 创建时加载的fixtures
+	pool     []gormx.Option // 额外的连接池配置
+}
+
+// fixtureFile fixture文件，fsys 为空时从本地文件系统读取
+type fixtureFile struct {
+	fsys fs.FS
+	path string
+}
+
+func newOptions(opts ...Option) Options {
+	var opt Options
+	for _, o := range opts {
+		o(&opt)
+	}
+	return opt
+}
+
+// Models 设置自动迁移的模型，fixtures 按模型的表名匹配
+func Models(models ...interface{}) Option {
+	return func(o *Options) {
+		o.models = append(o.models, models...)
+	}
+}
+
+// Fixtures 设置创建时加载的fixture文件，支持 .yaml/.yml/.json，按顺序加载
+func Fixtures(paths ...string) Option {
+	return func(o *Options) {
+		for _, path := range paths {
+			o.fixtures = append(o.fixtures, fixtureFile{path: path})
+		}
+	}
+}
+
+// FixturesFS 从 fsys 加载fixture文件，可配合 embed.FS 使用
+func FixturesFS(fsys fs.FS, paths ...string) Option {
+	return func(o *Options) {
+		for _, path := range paths {
+			o.fixtures = append(o.fixtures, fixtureFile{fsys: fsys, path: path})
+		}
+	}
+}
+
+// PoolOptions 设置额外的连接池配置，如 gormx.Plugin、gormx.Logger
+func PoolOptions(opts ...gormx.Option) Option {
+	return func(o *Options) {
+		o.pool = append(o.pool, opts...)
+	}
+}
@@ -0,0 +1,195 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hchicken/pkg-go/logx"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sourceDir gormx 源码目录，包含 gormxtest、migrate 等子包
+var sourceDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return file[:strings.LastIndex(file, "/")+1]
+}()
+
+// traceIDKey 上下文中trace id的key
+type traceIDKey struct{}
+
+// ContextWithTraceID 在上下文中设置trace id，SQL日志会附带该id
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext 获取上下文中的trace id
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// LoggerOption ...
+type LoggerOption func(*QueryLogger)
+
+// LogIns 设置输出的 logx 日志实例，默认 logx.Console()
+func LogIns(ins *logx.LoggerIns) LoggerOption {
+	return func(l *QueryLogger) {
+		l.ins = ins
+	}
+}
+
+// LogLevel 设置日志级别，默认 logger.Warn，只输出错误和慢查询
+func LogLevel(level logger.LogLevel) LoggerOption {
+	return func(l *QueryLogger) {
+		l.level = level
+	}
+}
+
+// SlowThreshold 设置慢查询阈值，默认200ms，为0时不记录慢查询
+func SlowThreshold(threshold time.Duration) LoggerOption {
+	return func(l *QueryLogger) {
+		l.slowThreshold = threshold
+	}
+}
+
+// IgnoreRecordNotFound 是否忽略 gorm.ErrRecordNotFound 错误，默认忽略
+func IgnoreRecordNotFound(b bool) LoggerOption {
+	return func(l *QueryLogger) {
+		l.ignoreNotFound = b
+	}
+}
+
+// TraceIDFunc 自定义从上下文获取trace id的方法，默认使用 TraceIDFromContext
+func TraceIDFunc(fn func(context.Context) string) LoggerOption {
+	return func(l *QueryLogger) {
+		l.traceID = fn
+	}
+}
+
+// QueryLogger 通过 logx 输出SQL日志的 gorm logger，错误以error级别、慢查询以warn级别输出，
+// SetDebug 开启时(logger.Info)所有SQL以info级别输出
+type QueryLogger struct {
+	ins            *logx.LoggerIns
+	level          logger.LogLevel
+	slowThreshold  time.Duration
+	ignoreNotFound bool
+	traceID        func(context.Context) string
+}
+
+// NewQueryLogger 创建SQL日志
+func NewQueryLogger(opts ...LoggerOption) *QueryLogger {
+	l := &QueryLogger{
+		level:          logger.Warn,
+		slowThreshold:  200 * time.Millisecond,
+		ignoreNotFound: true,
+		traceID:        TraceIDFromContext,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.ins == nil {
+		l.ins = logx.Console()
+	}
+	return l
+}
+
+// LogMode 实现 logger.Interface
+func (l *QueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	newLogger := *l
+	newLogger.level = level
+	return &newLogger
+}
+
+// Info 实现 logger.Interface
+func (l *QueryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		l.entry(ctx).Infof(msg, data...)
+	}
+}
+
+// Warn 实现 logger.Interface
+func (l *QueryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		l.entry(ctx).Warnf(msg, data...)
+	}
+}
+
+// Error 实现 logger.Interface
+func (l *QueryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		l.entry(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace 实现 logger.Interface，输出SQL、耗时和影响行数
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !(l.ignoreNotFound && errors.Is(err, gorm.ErrRecordNotFound)):
+		l.traceEntry(ctx, elapsed, fc).WithError(err).Error("sql error")
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		l.traceEntry(ctx, elapsed, fc).Warnf("slow sql >= %v", l.slowThreshold)
+	case l.level >= logger.Info:
+		l.traceEntry(ctx, elapsed, fc).Info("sql")
+	}
+}
+
+// entry 附带trace id的日志
+func (l *QueryLogger) entry(ctx context.Context) *logrus.Entry {
+	fields := logrus.Fields{}
+	if file := callerFile(); file != "" {
+		fields["file"] = file
+	}
+	if traceID := l.traceID(ctx); traceID != "" {
+		fields["trace_id"] = traceID
+	}
+	return l.ins.WithFields(fields)
+}
+
+// traceEntry 附带SQL、耗时和影响行数的日志
+func (l *QueryLogger) traceEntry(ctx context.Context, elapsed time.Duration, fc func() (string, int64)) *logrus.Entry {
+	sql, rows := fc()
+	fields := logrus.Fields{
+		"sql":     sql,
+		"elapsed": fmt.Sprintf("%.3fms", float64(elapsed.Nanoseconds())/1e6),
+		"rows":    rows,
+	}
+	if rows == -1 {
+		fields["rows"] = "-"
+	}
+	return l.entry(ctx).WithFields(fields)
+}
+
+// callerFile 跳过 gormx 和 gorm 的调用栈，返回业务代码的文件和行号
+func callerFile() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if !internalFile(frame.File) {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// internalFile 是否为 gormx 或 gorm 的源码文件，测试文件除外
+func internalFile(file string) bool {
+	if strings.HasSuffix(file, "_test.go") {
+		return false
+	}
+	return strings.HasPrefix(file, sourceDir) || strings.Contains(file, "gorm.io/")
+}
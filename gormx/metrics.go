@@ -0,0 +1,182 @@
+package gormx
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// metricsStartKey 语句开始时间在语句中的key
+const metricsStartKey = "gormx:metrics_start"
+
+// defaultMetricsBuckets 默认耗时分布区间上限
+var defaultMetricsBuckets = []time.Duration{
+	time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 500 * time.Millisecond, time.Second, 5 * time.Second,
+}
+
+// QueryStat 按表和操作统计的查询指标
+type QueryStat struct {
+	Table     string          `json:"table"`
+	Operation string          `json:"operation"` // create/query/update/delete/row/raw
+	Count     uint64          `json:"count"`     // 执行次数
+	Errors    uint64          `json:"errors"`    // 错误次数，不含 gorm.ErrRecordNotFound
+	Total     time.Duration   `json:"total"`     // 总耗时
+	Buckets   []time.Duration `json:"buckets"`   // 耗时分布区间上限
+	Counts    []uint64        `json:"counts"`    // 各区间的次数，最后一个为超过最大区间的次数
+}
+
+// metricsKey 指标分组
+type metricsKey struct {
+	table     string
+	operation string
+}
+
+// MetricsOption ...
+type MetricsOption func(*QueryMetrics)
+
+// MetricsBuckets 设置耗时分布区间上限
+func MetricsBuckets(buckets ...time.Duration) MetricsOption {
+	return func(m *QueryMetrics) {
+		m.buckets = append([]time.Duration(nil), buckets...)
+		sort.Slice(m.buckets, func(i, j int) bool { return m.buckets[i] < m.buckets[j] })
+	}
+}
+
+// MetricsObserver 设置每条语句执行后的回调，可用于对接 Prometheus 等监控系统
+func MetricsObserver(fn func(table, operation string, elapsed time.Duration, err error)) MetricsOption {
+	return func(m *QueryMetrics) {
+		m.observer = fn
+	}
+}
+
+// QueryMetrics 查询指标插件，按表和操作统计耗时分布和错误数
+type QueryMetrics struct {
+	sync.RWMutex
+	buckets  []time.Duration
+	observer func(table, operation string, elapsed time.Duration, err error)
+	stats    map[metricsKey]*QueryStat
+}
+
+// NewQueryMetrics 创建查询指标插件，通过 db.Use 注册
+func NewQueryMetrics(opts ...MetricsOption) *QueryMetrics {
+	m := &QueryMetrics{
+		buckets: defaultMetricsBuckets,
+		stats:   make(map[metricsKey]*QueryStat),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Name 实现 gorm.Plugin
+func (m *QueryMetrics) Name() string {
+	return "gormx:metrics"
+}
+
+// Initialize 实现 gorm.Plugin，注册回调
+func (m *QueryMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	errs := []error{
+		cb.Create().Before("*").Register("gormx:metrics_before_create", m.before),
+		cb.Create().After("*").Register("gormx:metrics_after_create", m.after("create")),
+		cb.Query().Before("*").Register("gormx:metrics_before_query", m.before),
+		cb.Query().After("*").Register("gormx:metrics_after_query", m.after("query")),
+		cb.Update().Before("*").Register("gormx:metrics_before_update", m.before),
+		cb.Update().After("*").Register("gormx:metrics_after_update", m.after("update")),
+		cb.Delete().Before("*").Register("gormx:metrics_before_delete", m.before),
+		cb.Delete().After("*").Register("gormx:metrics_after_delete", m.after("delete")),
+		cb.Row().Before("*").Register("gormx:metrics_before_row", m.before),
+		cb.Row().After("*").Register("gormx:metrics_after_row", m.after("row")),
+		cb.Raw().Before("*").Register("gormx:metrics_before_raw", m.before),
+		cb.Raw().After("*").Register("gormx:metrics_after_raw", m.after("raw")),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before 记录语句开始时间
+func (m *QueryMetrics) before(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+// after 统计语句耗时和错误
+func (m *QueryMetrics) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(value.(time.Time))
+
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		m.observe(db.Statement.Table, operation, elapsed, err)
+		if m.observer != nil {
+			m.observer(db.Statement.Table, operation, elapsed, err)
+		}
+	}
+}
+
+// observe 累加指标
+func (m *QueryMetrics) observe(table, operation string, elapsed time.Duration, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	key := metricsKey{table: table, operation: operation}
+	stat, ok := m.stats[key]
+	if !ok {
+		stat = &QueryStat{
+			Table:     table,
+			Operation: operation,
+			Buckets:   m.buckets,
+			Counts:    make([]uint64, len(m.buckets)+1),
+		}
+		m.stats[key] = stat
+	}
+
+	stat.Count++
+	stat.Total += elapsed
+	if err != nil {
+		stat.Errors++
+	}
+	i := sort.Search(len(m.buckets), func(i int) bool { return elapsed <= m.buckets[i] })
+	stat.Counts[i]++
+}
+
+// Snapshot 返回当前指标的副本，按表和操作排序
+func (m *QueryMetrics) Snapshot() []QueryStat {
+	m.RLock()
+	defer m.RUnlock()
+
+	stats := make([]QueryStat, 0, len(m.stats))
+	for _, stat := range m.stats {
+		s := *stat
+		s.Counts = append([]uint64(nil), stat.Counts...)
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Table != stats[j].Table {
+			return stats[i].Table < stats[j].Table
+		}
+		return stats[i].Operation < stats[j].Operation
+	})
+	return stats
+}
+
+// Reset 重置指标
+func (m *QueryMetrics) Reset() {
+	m.Lock()
+	m.stats = make(map[metricsKey]*QueryStat)
+	m.Unlock()
+}
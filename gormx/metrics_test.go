@@ -0,0 +1,84 @@
+package gormx
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hchicken/pkg-go/logx"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestQueryLoggerAndMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	out := logrus.New()
+	out.SetOutput(&buf)
+	out.SetLevel(logrus.DebugLevel)
+	out.SetFormatter(&logrus.JSONFormatter{})
+
+	metrics := NewQueryMetrics(MetricsBuckets(time.Hour))
+	pool, err := NewDBPool(
+		Driver(DriverSQLite),
+		Name(filepath.Join(t.TempDir(), "metrics.db")),
+		Logger(NewQueryLogger(LogIns(&logx.LoggerIns{Logger: out}), SlowThreshold(time.Nanosecond))),
+		Plugin(metrics),
+	)
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	metrics.Reset()
+	buf.Reset()
+
+	ctx := ContextWithTraceID(context.Background(), "trace-1")
+	if err := db.WithContext(ctx).Create(&testUser{Name: "tom"}).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var users []testUser
+	if err := db.WithContext(ctx).Where("unknown = 1").Find(&users).Error; err == nil {
+		t.Fatal("expected error for unknown column")
+	}
+
+	log := buf.String()
+	if !strings.Contains(log, `"trace_id":"trace-1"`) || !strings.Contains(log, "slow sql") || !strings.Contains(log, "sql error") {
+		t.Errorf("expected slow and error logs with trace id, got %s", log)
+	}
+	if strings.Contains(log, "logger.go") || !strings.Contains(log, `metrics_test.go:`) {
+		t.Errorf("expected caller file in log, got %s", log)
+	}
+	buf.Reset()
+	if _, err := NewRepository[testUser](db).Count(ctx); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if log := buf.String(); !strings.Contains(log, `metrics_test.go:`) {
+		t.Errorf("expected caller file outside gormx, got %s", log)
+	}
+
+	stats := metrics.Snapshot()
+	if len(stats) != 2 {
+		t.Fatalf("expected create and query stats, got %+v", stats)
+	}
+	create, query := stats[0], stats[1]
+	if create.Table != "test_users" || create.Operation != "create" || create.Count != 1 || create.Counts[0] != 1 {
+		t.Errorf("unexpected create stat: %+v", create)
+	}
+	if query.Operation != "query" || query.Errors != 1 {
+		t.Errorf("unexpected query stat: %+v", query)
+	}
+
+	buf.Reset()
+	if err := db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)}).Find(&users).Error; err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected silent logger to skip output, got %s", buf.String())
+	}
+}
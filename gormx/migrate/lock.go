@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Locker 迁移锁，保证多个副本同时启动时只有一个执行迁移
+type Locker interface {
+	// Lock 获取锁，返回释放锁的方法
+	Lock(ctx context.Context) (unlock func() error, err error)
+}
+
+// advisoryLocker 基于数据库 advisory lock 的迁移锁，锁与会话绑定，进程退出时自动释放
+type advisoryLocker struct {
+	db      *gorm.DB
+	key     string
+	timeout time.Duration
+}
+
+// Lock 实现 Locker
+func (l *advisoryLocker) Lock(ctx context.Context) (func() error, error) {
+	dialect := l.db.Dialector.Name()
+	switch dialect {
+	case "mysql", "postgres":
+	case "sqlite":
+		// sqlite 写操作本身串行，无需加锁
+		return func() error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("advisory lock not supported for %s, use UseLocker", dialect)
+	}
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect == "mysql" {
+		return l.lockMySQL(ctx, conn)
+	}
+	return l.lockPostgres(ctx, conn)
+}
+
+// lockMySQL 使用 GET_LOCK 获取锁，GET_LOCK 的锁名在整个实例内共享，锁名加上库名避免不同库的迁移互相阻塞
+func (l *advisoryLocker) lockMySQL(ctx context.Context, conn *sql.Conn) (func() error, error) {
+	var database sql.NullString
+	if err := conn.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&database); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock %s: %w", l.key, err)
+	}
+	key := mysqlLockKey(database.String, l.key)
+
+	var acquired sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", key, int(l.timeout.Seconds())).Scan(&acquired)
+	if err == nil && (!acquired.Valid || acquired.Int64 != 1) {
+		err = fmt.Errorf("timeout after %v", l.timeout)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock %s: %w", key, err)
+	}
+
+	return func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", key)
+		return err
+	}, nil
+}
+
+// lockPostgres 使用 pg_advisory_lock 获取锁，锁名哈希为 bigint
+func (l *advisoryLocker) lockPostgres(ctx context.Context, conn *sql.Conn) (func() error, error) {
+	id := lockID(l.key)
+	lockCtx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+	if _, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", id); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock %s: %w", l.key, err)
+	}
+
+	return func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", id)
+		return err
+	}, nil
+}
+
+// mysqlLockKey 生成 库名.锁名 形式的锁名，超过 MySQL 64 字符限制时使用哈希
+func mysqlLockKey(database, key string) string {
+	name := database + "." + key
+	if len(name) > 64 {
+		return fmt.Sprintf("migrate.%x", lockID(name))
+	}
+	return name
+}
+
+// lockID 锁名转换为 advisory lock 使用的 bigint
+func lockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,241 @@
+// Package migrate 版本化数据库迁移，支持 Go 代码和 SQL 文件注册迁移，
+// 迁移记录保存在 schema_migrations 表，执行时通过锁保证只有一个副本迁移
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 迁移
+type Migration struct {
+	Version string               // 版本号，按字符串排序，建议使用时间戳如 20240101120000
+	Name    string               // 名称
+	Up      func(*gorm.DB) error // 升级
+	Down    func(*gorm.DB) error // 回滚
+}
+
+// MigrationStatus 迁移状态
+type MigrationStatus struct {
+	Version   string     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`    // 是否已执行
+	AppliedAt *time.Time `json:"applied_at"` // 执行时间
+	Missing   bool       `json:"missing"`    // 已执行但未注册
+}
+
+// schemaMigration 迁移记录
+type schemaMigration struct {
+	Version   string    `gorm:"type:varchar(64);primaryKey"`
+	Name      string    `gorm:"type:varchar(255)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	opts       Options
+	migrations []*Migration
+}
+
+// New 创建迁移执行器
+func New(db *gorm.DB, opts ...Option) *Migrator {
+	options := newOptions(opts...)
+	if options.locker == nil {
+		options.locker = &advisoryLocker{db: db, key: options.lockKey, timeout: options.lockTimeout}
+	}
+	return &Migrator{db: db, opts: options}
+}
+
+// Register 注册迁移
+func (m *Migrator) Register(migrations ...*Migration) error {
+	for _, migration := range migrations {
+		if migration.Version == "" {
+			return fmt.Errorf("migration version is required")
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("migration %s has no up", migration.Version)
+		}
+		if m.find(migration.Version) != nil {
+			return fmt.Errorf("duplicate migration version %s", migration.Version)
+		}
+		m.migrations = append(m.migrations, migration)
+	}
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return nil
+}
+
+// find 按版本查找迁移
+func (m *Migrator) find(version string) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+// Up 执行所有未执行的迁移
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(applied map[string]schemaMigration) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.up(ctx, migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down 按版本倒序回滚最近执行的 n 个迁移
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.withLock(ctx, func(applied map[string]schemaMigration) error {
+		for _, migration := range m.lastApplied(applied, n) {
+			if err := m.down(ctx, migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Redo 回滚并重新执行最近一个迁移
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func(applied map[string]schemaMigration) error {
+		for _, migration := range m.lastApplied(applied, 1) {
+			if err := m.down(ctx, migration); err != nil {
+				return err
+			}
+			if err := m.up(ctx, migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status 返回所有迁移的执行状态，按版本排序
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied, status.AppliedAt = true, &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range applied {
+		if m.find(version) == nil {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, MigrationStatus{
+				Version: version, Name: record.Name, Applied: true, AppliedAt: &appliedAt, Missing: true,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// withLock 获取迁移锁，确保迁移记录表存在后执行
+func (m *Migrator) withLock(ctx context.Context, fn func(applied map[string]schemaMigration) error) (err error) {
+	unlock, err := m.opts.locker.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); err == nil && unlockErr != nil {
+			err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+		}
+	}()
+
+	if err := m.table(ctx).AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create %s: %w", m.opts.table, err)
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+// table 迁移记录表
+func (m *Migrator) table(ctx context.Context) *gorm.DB {
+	return m.db.WithContext(ctx).Table(m.opts.table)
+}
+
+// applied 查询已执行的迁移
+func (m *Migrator) applied(ctx context.Context) (map[string]schemaMigration, error) {
+	applied := make(map[string]schemaMigration)
+	if !m.db.WithContext(ctx).Migrator().HasTable(m.opts.table) {
+		return applied, nil
+	}
+
+	var records []schemaMigration
+	if err := m.table(ctx).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", m.opts.table, err)
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// lastApplied 返回最近执行的 n 个已注册迁移，按版本倒序
+func (m *Migrator) lastApplied(applied map[string]schemaMigration, n int) []*Migration {
+	var migrations []*Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(migrations) < n; i-- {
+		if _, ok := applied[m.migrations[i].Version]; ok {
+			migrations = append(migrations, m.migrations[i])
+		}
+	}
+	return migrations
+}
+
+// up 在事务中执行迁移并写入记录
+func (m *Migrator) up(ctx context.Context, migration *Migration) error {
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		record := schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		return tx.Table(m.opts.table).Create(&record).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// down 在事务中回滚迁移并删除记录
+func (m *Migrator) down(ctx context.Context, migration *Migration) error {
+	if migration.Down == nil {
+		return fmt.Errorf("migration %s_%s has no down", migration.Version, migration.Name)
+	}
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Table(m.opts.table).Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rollback %s_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
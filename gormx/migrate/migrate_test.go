@@ -0,0 +1,116 @@
+package migrate
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSplitStatements(t *testing.T) {
+	got := splitStatements("CREATE TABLE a (x TEXT DEFAULT ';'); -- drop; comment\nINSERT INTO a VALUES ('b;c');\n")
+	want := []string{"CREATE TABLE a (x TEXT DEFAULT ';')", "INSERT INTO a VALUES ('b;c')"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSplitStatementsQuoting(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		want    []string
+	}{
+		"dollar quoting": {
+			content: "CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN NEW.a := 1; RETURN NEW; END; $$ LANGUAGE plpgsql;\n" +
+				"CREATE FUNCTION g() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;SELECT $1;",
+			want: []string{
+				"CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN NEW.a := 1; RETURN NEW; END; $$ LANGUAGE plpgsql",
+				"CREATE FUNCTION g() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql",
+				"SELECT $1",
+			},
+		},
+		"block comment": {
+			content: "/* drop a; drop b; */ SELECT 1; SELECT /*!40101 2; */ 3;",
+			want:    []string{"/* drop a; drop b; */ SELECT 1", "SELECT /*!40101 2; */ 3"},
+		},
+		"escaped quote": {
+			content: `INSERT INTO a VALUES ('it\'s; ok'), ('a''b;c'); SELECT "x\";y";`,
+			want:    []string{`INSERT INTO a VALUES ('it\'s; ok'), ('a''b;c')`, `SELECT "x\";y"`},
+		},
+		"statement block": {
+			content: "SELECT 1;\n-- +migrate StatementBegin\nCREATE TRIGGER t BEGIN\n  UPDATE a SET x = 1;\nEND;\n-- +migrate StatementEnd\nSELECT 2;",
+			want:    []string{"SELECT 1", "CREATE TRIGGER t BEGIN\n  UPDATE a SET x = 1;\nEND", "SELECT 2"},
+		},
+	}
+	for name, c := range cases {
+		if got := splitStatements(c.content); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expected %q, got %q", name, c.want, got)
+		}
+	}
+}
+
+func TestMySQLLockKey(t *testing.T) {
+	if key := mysqlLockKey("orders", "schema_migrations"); key != "orders.schema_migrations" {
+		t.Errorf("expected database in lock key, got %s", key)
+	}
+	long := strings.Repeat("x", 64)
+	if a, b := mysqlLockKey(long, "m"), mysqlLockKey(long+"y", "m"); len(a) > 64 || a == b {
+		t.Errorf("expected distinct hashed keys within 64 chars, got %s %s", a, b)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+
+	m := New(db)
+	err = m.LoadFS(fstest.MapFS{
+		"migrations/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);")},
+		"migrations/0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	}, "migrations")
+	if err != nil {
+		t.Fatalf("LoadFS failed: %v", err)
+	}
+	err = m.Register(&Migration{
+		Version: "0002",
+		Name:    "add_email",
+		Up:      func(tx *gorm.DB) error { return tx.Exec("ALTER TABLE users ADD COLUMN email TEXT").Error },
+		Down:    func(tx *gorm.DB) error { return tx.Exec("ALTER TABLE users DROP COLUMN email").Error },
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if !db.Migrator().HasColumn("users", "email") {
+		t.Error("expected email column after Up")
+	}
+
+	if err := m.Redo(ctx); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if err := m.Down(ctx, 1); err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if db.Migrator().HasColumn("users", "email") {
+		t.Error("expected email column dropped after Down")
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("unexpected status: %+v", statuses)
+	}
+}
@@ -0,0 +1,56 @@
+package migrate
+
+import "time"
+
+// Option ...
+type Option func(*Options)
+
+// Options migrate options
+type Options struct {
+	table       string        // 迁移记录表，默认 schema_migrations
+	lockKey     string        // 迁移锁名称，默认与迁移记录表相同
+	lockTimeout time.Duration // 获取迁移锁的超时时间，默认1分钟
+	locker      Locker        // 自定义迁移锁，默认使用数据库advisory lock
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		table:       "schema_migrations",
+		lockTimeout: time.Minute,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.lockKey == "" {
+		opt.lockKey = opt.table
+	}
+	return opt
+}
+
+// Table 设置迁移记录表
+func Table(table string) Option {
+	return func(o *Options) {
+		o.table = table
+	}
+}
+
+// LockKey 设置迁移锁名称
+func LockKey(key string) Option {
+	return func(o *Options) {
+		o.lockKey = key
+	}
+}
+
+// LockTimeout 设置获取迁移锁的超时时间
+func LockTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.lockTimeout = timeout
+	}
+}
+
+// UseLocker 使用自定义迁移锁，如基于 Redis 的分布式锁
+func UseLocker(locker Locker) Option {
+	return func(o *Options) {
+		o.locker = locker
+	}
+}
@@ -0,0 +1,193 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// LoadFS 从文件系统(如 embed.FS)的目录中加载 SQL 迁移，
+// 文件名格式为 <version>_<name>.up.sql 和 <version>_<name>.down.sql
+func (m *Migrator) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations dir: %w", err)
+	}
+
+	loaded := make(map[string]*Migration)
+	var order []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		version, name, direction, ok := parseFileName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, exists := loaded[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			loaded[version] = migration
+			order = append(order, version)
+		} else if migration.Name != name {
+			return fmt.Errorf("migration %s has different names: %s, %s", version, migration.Name, name)
+		}
+
+		statements := splitStatements(string(content))
+		if direction == "up" {
+			migration.Up = execStatements(statements)
+		} else {
+			migration.Down = execStatements(statements)
+		}
+	}
+
+	for _, version := range order {
+		if err := m.Register(loaded[version]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFileName 解析迁移文件名
+func parseFileName(filename string) (version, name, direction string, ok bool) {
+	base := strings.TrimSuffix(filename, ".sql")
+	if base == filename {
+		return "", "", "", false
+	}
+	switch {
+	case strings.HasSuffix(base, ".up"):
+		direction = "up"
+	case strings.HasSuffix(base, ".down"):
+		direction = "down"
+	default:
+		return "", "", "", false
+	}
+	base = strings.TrimSuffix(base, "."+direction)
+
+	version, name, _ = strings.Cut(base, "_")
+	if version == "" {
+		return "", "", "", false
+	}
+	return version, name, direction, true
+}
+
+// execStatements 依次执行SQL语句
+func execStatements(statements []string) func(*gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+const (
+	statementBegin = "-- +migrate StatementBegin" // 到 StatementEnd 之间的内容作为一条语句，不按分号拆分
+	statementEnd   = "-- +migrate StatementEnd"
+)
+
+// splitStatements 按分号拆分SQL语句，忽略以下位置的分号：引号内(支持反斜杠转义和两个引号的转义)、
+// -- 和 /* */ 注释、Postgres 的 $$ 或 $tag$ 引用体，以及 StatementBegin 和 StatementEnd 之间的内容。
+// 分隔符均为ASCII字符，按字节扫描不会截断UTF-8字符；块注释保留在语句中
+func splitStatements(content string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, strings.TrimSuffix(statement, ";"))
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(content); {
+		rest := content[i:]
+		switch {
+		case rest[0] == '\'' || rest[0] == '"' || rest[0] == '`':
+			n := quotedLen(rest)
+			current.WriteString(rest[:n])
+			i += n
+		case rest[0] == '$' && dollarTag(rest) != "":
+			tag := dollarTag(rest)
+			n := untilAfter(rest, len(tag), tag)
+			current.WriteString(rest[:n])
+			i += n
+		case strings.HasPrefix(rest, "/*"):
+			n := untilAfter(rest, 2, "*/")
+			current.WriteString(rest[:n])
+			i += n
+		case strings.HasPrefix(rest, statementBegin):
+			flush()
+			body := untilAfter(rest, 0, "\n")
+			end := strings.Index(rest[body:], statementEnd)
+			if end < 0 {
+				end = len(rest) - body
+			}
+			current.WriteString(rest[body : body+end])
+			flush()
+			i += untilAfter(rest, body+end, "\n")
+		case strings.HasPrefix(rest, "--"):
+			i += untilAfter(rest, 2, "\n")
+		case rest[0] == ';':
+			flush()
+			i++
+		default:
+			current.WriteByte(rest[0])
+			i++
+		}
+	}
+	flush()
+	return statements
+}
+
+// quotedLen 返回 s 开头的引号字符串的长度，单双引号内的反斜杠转义下一个字符
+func quotedLen(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// dollarTag 返回 s 开头的 Postgres 引用标记，如 $$、$body$，不是引用标记时返回空
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// untilAfter 返回 s 中 from 之后第一个 sep 结束的位置，不存在时返回 len(s)
+func untilAfter(s string, from int, sep string) int {
+	if index := strings.Index(s[from:], sep); index >= 0 {
+		return from + index + len(sep)
+	}
+	return len(s)
+}
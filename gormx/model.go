@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // TabBaseModel 模型定义基类
@@ -14,21 +16,26 @@ type TabBaseModel struct {
 	UpdatedAt JsonTime `json:"updated_at" gorm:"type:TIMESTAMP;default:CURRENT_TIMESTAMP on update current_timestamp;comment:'更新时间'"`
 }
 
-// JsonTime 自定义时间格式(用来处理字符串时间格式)
+// TabSoftDeleteModel 带软删除的模型基类，Delete 时只写入删除时间，查询自动过滤已删除记录
+type TabSoftDeleteModel struct {
+	TabBaseModel
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index;comment:'删除时间'"`
+}
+
+// JsonTime 自定义时间格式(用来处理字符串时间格式)，格式和时区通过 SetTimeLayout、SetTimeLocation 设置
 type JsonTime struct {
 	time.Time
 }
 
 // MarshalJSON 序列化json
 func (t JsonTime) MarshalJSON() ([]byte, error) {
-	formatted := fmt.Sprintf("\"%s\"", t.Format(time.DateTime))
-	return []byte(formatted), nil
+	return marshalTime(t.Time, timeLayout), nil
 }
 
-// UnmarshalJSON 反序列号json
+// UnmarshalJSON 反序列号json，null 和空字符串解析为零值
 func (t *JsonTime) UnmarshalJSON(b []byte) error {
-	now, err := time.ParseInLocation(`"`+time.DateTime+`"`, string(b), time.Local)
-	t.Time = now
+	value, _, err := unmarshalTime(b, timeLayout)
+	t.Time = value
 	return err
 }
 
@@ -41,17 +48,17 @@ func (t JsonTime) Value() (driver.Value, error) {
 	return t.Time, nil
 }
 
-// Scan ...
+// Scan 支持 time.Time 以及 sqlite 等驱动返回的字符串和字节
 func (t *JsonTime) Scan(v interface{}) error {
-	value, ok := v.(time.Time)
-	if ok {
-		*t = JsonTime{Time: value}
-		return nil
+	value, _, err := scanTime(v)
+	if err != nil {
+		return fmt.Errorf("can not convert %v to JSONTime: %w", v, err)
 	}
-	return fmt.Errorf("can not convert %v to JSONTime", v)
+	*t = JsonTime{Time: value}
+	return nil
 }
 
 // FormatTime 时间转换
 func (t *JsonTime) FormatTime() string {
-	return t.Format(time.DateTime)
+	return formatTime(t.Time, timeLayout)
 }
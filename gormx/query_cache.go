@@ -0,0 +1,270 @@
+package gormx
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/hchicken/pkg-go/cache"
+	"github.com/hchicken/pkg-go/stringx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	defaultCacheTTL = time.Minute    // 查询缓存默认过期时间
+	cacheKeyPrefix  = "gormx:cache:" // 查询缓存key前缀
+)
+
+// cacheEntry 缓存的查询结果，使用 gob 编码，保留 json:"-" 字段和时间的精度、时区
+type cacheEntry struct {
+	Data  []byte
+	Total *int64
+}
+
+func init() {
+	// ScanModel 为 map 时，时间列以 interface{} 保存
+	gob.Register(time.Time{})
+}
+
+// cacheable 是否使用查询缓存，游标分页和事务中的查询不缓存
+func (d *Database) cacheable() bool {
+	if d.opts.Cache == nil || d.opts.CursorPage != nil || d.opts.ScanModel == nil {
+		return false
+	}
+	if d.opts.Ctx != nil {
+		if _, ok := TxFromContext(d.opts.Ctx); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedQuery 命中缓存时直接填充 ScanModel 和 Total，否则执行 run 并写入缓存。
+// 缓存读写失败时只记录日志，不影响查询
+func (d *Database) cachedQuery(operation string, run func() error) error {
+	if !d.cacheable() {
+		return run()
+	}
+
+	key, err := d.cacheKey(operation)
+	if err != nil {
+		d.cacheWarn(err)
+		return run()
+	}
+	hit, err := d.loadCache(key)
+	if err != nil {
+		d.cacheWarn(err)
+	}
+	if hit {
+		return nil
+	}
+
+	if err := run(); err != nil {
+		return err
+	}
+	if err := d.storeCache(key); err != nil {
+		d.cacheWarn(err)
+	}
+	return nil
+}
+
+// cacheKey 生成缓存key: 前缀 + 表名 + 表版本 + 查询参数的md5
+func (d *Database) cacheKey(operation string) (string, error) {
+	table, err := d.cacheTable(d.opts.DbModel)
+	if err != nil {
+		return "", err
+	}
+	conditions, err := d.decodeAndCleanConditions()
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]interface{}{
+		"operation":  operation,
+		"model":      fmt.Sprintf("%T", d.opts.DbModel),
+		"scan":       fmt.Sprintf("%T", d.opts.ScanModel),
+		"conditions": conditions,
+		"where":      d.exprs,
+		"in":         d.opts.In,
+		"like":       d.opts.Like,
+		"page":       d.opts.Page,
+		"limit":      d.opts.Limit,
+		"offset":     d.opts.Offset,
+		"total":      d.opts.Total != nil,
+		"sort_field": d.opts.SortField,
+		"sort":       d.opts.Sort,
+		"start_time": d.opts.StartTime,
+		"end_time":   d.opts.EndTime,
+		"time_field": d.opts.TimeField,
+		"filters":    d.opts.Filters,
+		"filter":     d.opts.FilterStruct,
+		"allowed":    d.opts.AllowedFields,
+		"sortable":   d.opts.SortFields,
+		"unscoped":   d.opts.Unscoped,
+	}
+	if tenant, ok := TenantFromContext(d.Context()); ok {
+		params["tenant"] = tenant
+	}
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cache key: %w", err)
+	}
+
+	version, err := d.cacheVersion(table)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s:%d:%s", cacheKeyPrefix, table, version, stringx.StrToMd5(string(payload))), nil
+}
+
+// cacheTable 获取模型对应的表名
+func (d *Database) cacheTable(model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: d.db}
+	if err := stmt.Parse(model); err != nil {
+		return "", fmt.Errorf("failed to parse model: %w", err)
+	}
+	return stmt.Table, nil
+}
+
+// cacheVersionKey 表版本key，表数据变更时递增，使该表的缓存全部失效
+func cacheVersionKey(table string) string {
+	return cacheKeyPrefix + "version:" + table
+}
+
+// cacheVersion 获取表版本
+func (d *Database) cacheVersion(table string) (int64, error) {
+	conn := d.opts.Cache.GetConnection()
+	defer conn.Close()
+
+	version, err := redis.Int64(conn.Do("GET", cacheVersionKey(table)))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return 0, fmt.Errorf("failed to get cache version: %w", err)
+	}
+	return version, nil
+}
+
+// loadCache 读取缓存并填充 ScanModel 和 Total
+func (d *Database) loadCache(key string) (bool, error) {
+	conn := d.opts.Cache.GetConnection()
+	defer conn.Close()
+
+	data, err := redis.Bytes(conn.Do("GET", key))
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get cache: %w", err)
+	}
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return false, fmt.Errorf("failed to decode cache: %w", err)
+	}
+	if err := gob.NewDecoder(bytes.NewReader(entry.Data)).Decode(d.opts.ScanModel); err != nil {
+		return false, fmt.Errorf("failed to decode cache: %w", err)
+	}
+	if d.opts.Total != nil && entry.Total != nil {
+		*d.opts.Total = *entry.Total
+	}
+	return true, nil
+}
+
+// storeCache 写入缓存
+func (d *Database) storeCache(key string) error {
+	var data, value bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(d.opts.ScanModel); err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := gob.NewEncoder(&value).Encode(cacheEntry{Data: data.Bytes(), Total: d.opts.Total}); err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	ttl := d.opts.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	conn := d.opts.Cache.GetConnection()
+	defer conn.Close()
+	if _, err := conn.Do("SET", key, value.Bytes(), "PX", ttl.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+// invalidateCache 递增模型对应表的版本，使该表的查询缓存失效。
+// WithTx 事务中只记录写入的表，提交后再递增版本，避免提交前的旧数据被缓存到新版本下
+func (d *Database) invalidateCache(model interface{}) {
+	if d.opts.Cache == nil {
+		return
+	}
+	table, err := d.cacheTable(model)
+	if err == nil {
+		if pending, ok := d.Context().Value(txCacheKey{}).(*txCache); ok {
+			pending.add(d.opts.Cache, table)
+			return
+		}
+		err = incrCacheVersion(d.opts.Cache, table)
+	}
+	if err != nil {
+		d.db.Logger.Error(d.Context(), "failed to invalidate query cache: %v", err)
+	}
+}
+
+// incrCacheVersion 递增表版本
+func incrCacheVersion(pool cache.CachePool, table string) error {
+	conn := pool.GetConnection()
+	defer conn.Close()
+	_, err := conn.Do("INCR", cacheVersionKey(table))
+	return err
+}
+
+// txCacheKey 上下文中事务待失效缓存的key
+type txCacheKey struct{}
+
+// txCacheTable 事务中写入的表及其缓存
+type txCacheTable struct {
+	pool  cache.CachePool
+	table string
+}
+
+// txCache 事务中写入的表，提交后统一递增版本
+type txCache struct {
+	mu     sync.Mutex
+	tables []txCacheTable
+}
+
+// add 记录写入的表
+func (c *txCache) add(pool cache.CachePool, table string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.tables {
+		if t.pool == pool && t.table == table {
+			return
+		}
+	}
+	c.tables = append(c.tables, txCacheTable{pool: pool, table: table})
+}
+
+// flush 递增事务中写入的表的版本，失败时记录日志
+func (c *txCache) flush(ctx context.Context, log logger.Interface) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.tables {
+		if err := incrCacheVersion(t.pool, t.table); err != nil {
+			log.Error(ctx, "failed to invalidate query cache of %s: %v", t.table, err)
+		}
+	}
+	c.tables = nil
+}
+
+// cacheWarn 记录缓存读写失败
+func (d *Database) cacheWarn(err error) {
+	d.db.Logger.Warn(d.Context(), "query cache: %v", err)
+}
@@ -0,0 +1,213 @@
+package gormx
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hchicken/pkg-go/cache"
+)
+
+// memoryCache 内存实现的 cache.CachePool，只支持 GET/SET/INCR
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (c *memoryCache) GetConnection() cache.Connection { return &memoryConn{c: c} }
+func (c *memoryCache) Close() error                    { return nil }
+
+type memoryConn struct{ c *memoryCache }
+
+func (m *memoryConn) Close() error                      { return nil }
+func (m *memoryConn) Err() error                        { return nil }
+func (m *memoryConn) Send(string, ...interface{}) error { return nil }
+func (m *memoryConn) Flush() error                      { return nil }
+func (m *memoryConn) Receive() (interface{}, error)     { return nil, nil }
+func (m *memoryConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	c := m.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := args[0].(string)
+	switch cmd {
+	case "GET":
+		value, ok := c.data[key]
+		if !ok {
+			return nil, nil
+		}
+		return value, nil
+	case "SET":
+		c.data[key] = args[1].([]byte)
+		return "OK", nil
+	case "INCR":
+		n, _ := strconv.ParseInt(string(c.data[key]), 10, 64)
+		c.data[key] = []byte(strconv.FormatInt(n+1, 10))
+		return n + 1, nil
+	}
+	return nil, fmt.Errorf("unsupported command %s", cmd)
+}
+
+func TestQueryCache(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "cache.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	store := &memoryCache{data: make(map[string][]byte)}
+	repo := NewRepository[testUser](db, WithConnCache(store))
+	if err := repo.Create(context.Background(), &testUser{Name: "tom"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	list := func() ([]testUser, int64) {
+		users, total, err := repo.List(context.Background(), WithConnConditions(map[string]interface{}{"name": "tom"}))
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		return users, total
+	}
+	list()
+	// 绕过 Database 直接修改，缓存命中时看不到该修改
+	db.Model(&testUser{}).Where("name = ?", "tom").Update("age", 30)
+	users, total := list()
+	if total != 1 || len(users) != 1 || users[0].Age != 0 {
+		t.Errorf("expected cached result, got %d %+v", total, users)
+	}
+
+	if err := repo.Create(context.Background(), &testUser{Name: "tom"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	users, total = list()
+	if total != 2 || len(users) != 2 || users[1].Age != 30 {
+		t.Errorf("expected cache to be invalidated after create, got %d %+v", total, users)
+	}
+}
+
+func TestQueryCacheRepositoryWrites(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "cache.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	ctx := context.Background()
+	repo := NewRepository[testUser](db, WithConnCache(&memoryCache{data: make(map[string][]byte)}))
+	user := &testUser{Name: "tom"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, _, err := repo.List(ctx); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	user.Age = 30
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if users, _, _ := repo.List(ctx); len(users) != 1 || users[0].Age != 30 {
+		t.Errorf("expected cache to be invalidated after update, got %+v", users)
+	}
+	if got, _ := repo.Get(ctx, user.ID); got == nil || got.Age != 30 {
+		t.Errorf("expected updated record, got %+v", got)
+	}
+
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if users, total, _ := repo.List(ctx); total != 0 || len(users) != 0 {
+		t.Errorf("expected cache to be invalidated after delete, got %d %+v", total, users)
+	}
+}
+
+func TestQueryCacheTx(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "cache.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	if err := pool.GetConn().AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	store := &memoryCache{data: make(map[string][]byte)}
+	repo := NewRepository[testUser](pool.GetConn(), WithConnCache(store))
+	version := func() string {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return string(store.data[cacheVersionKey("test_users")])
+	}
+
+	// 事务提交后才递增版本
+	err = pool.WithTx(context.Background(), func(tx *Database) error {
+		if err := repo.Create(tx.Context(), &testUser{Name: "tom"}); err != nil {
+			return err
+		}
+		return tx.WithTx(tx.Context(), func(tx *Database) error {
+			if err := repo.Create(tx.Context(), &testUser{Name: "jerry"}); err != nil {
+				return err
+			}
+			if v := version(); v != "" {
+				t.Errorf("expected version unchanged before commit, got %s", v)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if v := version(); v != "1" {
+		t.Errorf("expected version incremented once after commit, got %s", v)
+	}
+
+	// 回滚时不递增版本
+	_ = pool.WithTx(context.Background(), func(tx *Database) error {
+		if err := repo.Create(tx.Context(), &testUser{Name: "rollback"}); err != nil {
+			return err
+		}
+		return context.Canceled
+	})
+	if v := version(); v != "1" {
+		t.Errorf("expected version unchanged after rollback, got %s", v)
+	}
+}
+
+type testCacheRow struct {
+	ID      int64    `json:"id"`
+	Secret  string   `json:"-"`
+	At      JsonTime `json:"at"`
+	Payload []byte   `json:"payload"`
+}
+
+func TestQueryCacheCodec(t *testing.T) {
+	store := &memoryCache{data: make(map[string][]byte)}
+	at := time.Date(2023, 7, 7, 10, 30, 15, 123456789, time.FixedZone("CST", 8*3600))
+	total := int64(1)
+	want := []testCacheRow{{ID: 1, Secret: "s3cret", At: JsonTime{Time: at}, Payload: []byte{0, 1}}}
+	if err := NewDatabase(WithConnCache(store), WithConnScanModel(&want), WithConnTotal(&total)).storeCache("key"); err != nil {
+		t.Fatalf("storeCache failed: %v", err)
+	}
+
+	var (
+		got      []testCacheRow
+		gotTotal int64
+	)
+	hit, err := NewDatabase(WithConnCache(store), WithConnScanModel(&got), WithConnTotal(&gotTotal)).loadCache("key")
+	if err != nil || !hit {
+		t.Fatalf("loadCache failed: %v, %v", hit, err)
+	}
+	if len(got) != 1 || gotTotal != 1 {
+		t.Fatalf("unexpected cached rows: %d %+v", gotTotal, got)
+	}
+	_, offset := got[0].At.Zone()
+	if got[0].Secret != "s3cret" || !got[0].At.Equal(at) || got[0].At.Nanosecond() != 123456789 ||
+		offset != 8*3600 || string(got[0].Payload) != "\x00\x01" {
+		t.Errorf("expected every field to round-trip, got %+v", got[0])
+	}
+}
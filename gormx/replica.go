@@ -0,0 +1,136 @@
+package gormx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// 从库选择策略
+const (
+	ReplicaRoundRobin = "round_robin" // 轮询
+	ReplicaLeastConn  = "least_conn"  // 使用中连接最少
+)
+
+// replica 从库配置，未设置DSN时沿用主库的驱动、账号和库名
+type replica struct {
+	uri  string
+	port string
+	dsn  string
+}
+
+// roundRobinPolicy 轮询选择从库
+type roundRobinPolicy struct {
+	next uint64
+}
+
+// Resolve 实现 dbresolver.Policy
+func (p *roundRobinPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	n := atomic.AddUint64(&p.next, 1)
+	return pools[(n-1)%uint64(len(pools))]
+}
+
+// leastConnPolicy 选择使用中连接最少的从库
+type leastConnPolicy struct{}
+
+// Resolve 实现 dbresolver.Policy
+func (leastConnPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	selected, least := pools[0], -1
+	for _, pool := range pools {
+		db, ok := pool.(*sql.DB)
+		if !ok {
+			continue
+		}
+		if inUse := db.Stats().InUse; least < 0 || inUse < least {
+			selected, least = pool, inUse
+		}
+	}
+	return selected
+}
+
+// newReplicaPolicy 根据名称创建从库选择策略
+func newReplicaPolicy(name string) (dbresolver.Policy, error) {
+	switch name {
+	case ReplicaRoundRobin, "":
+		return &roundRobinPolicy{}, nil
+	case ReplicaLeastConn:
+		return leastConnPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported replica policy: %s", name)
+	}
+}
+
+// registerReplicas 注册从库，读操作(Query、First、Count)路由到从库，写操作和事务使用主库
+func registerReplicas(pool *gorm.DB, o Options) (*dbresolver.DBResolver, error) {
+	if len(o.replicas) == 0 {
+		return nil, nil
+	}
+
+	dialectors := make([]gorm.Dialector, 0, len(o.replicas))
+	for _, r := range o.replicas {
+		replicaOptions := o
+		replicaOptions.uri, replicaOptions.port, replicaOptions.dsn = r.uri, r.port, r.dsn
+		dial, err := dialector(replicaOptions)
+		if err != nil {
+			return nil, err
+		}
+		dialectors = append(dialectors, dial)
+	}
+
+	policy, err := newReplicaPolicy(o.replicaPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: dialectors, Policy: policy})
+	if err := pool.Use(resolver); err != nil {
+		return nil, err
+	}
+	if err := configureReplicas(pool, resolver, o); err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+// configureReplicas 按连接池参数设置从库连接，未设置的参数保持默认值。
+// resolver 的连接中包含主库，主库参数已在打开连接时设置，这里跳过
+func configureReplicas(pool *gorm.DB, resolver *dbresolver.DBResolver, o Options) error {
+	primary, err := pool.DB()
+	if err != nil {
+		return err
+	}
+	return resolver.Call(func(connPool gorm.ConnPool) error {
+		db, ok := connPool.(*sql.DB)
+		if !ok || db == primary {
+			return nil
+		}
+		if o.MaxOpenConn > 0 {
+			db.SetMaxOpenConns(o.MaxOpenConn)
+		}
+		if o.MaxIdleConn > 0 {
+			db.SetMaxIdleConns(o.MaxIdleConn)
+		}
+		if o.ConnMaxLifetime > 0 {
+			db.SetConnMaxLifetime(o.ConnMaxLifetime)
+		}
+		return nil
+	})
+}
+
+// primaryKey 上下文中强制主库的key
+type primaryKey struct{}
+
+// ContextWithPrimary 强制使用该上下文的查询走主库，用于写后读一致性
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// isPrimary 上下文是否强制主库
+func isPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,118 @@
+package gormx
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// seedSQLite 创建sqlite库并写入一条记录
+func seedSQLite(t *testing.T, file, name string) {
+	t.Helper()
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(file))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	if err := db.Create(&testUser{Name: name}).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+}
+
+func TestReplicaRouting(t *testing.T) {
+	dir := t.TempDir()
+	primary, replica := filepath.Join(dir, "primary.db"), filepath.Join(dir, "replica.db")
+	seedSQLite(t, primary, "primary")
+	seedSQLite(t, replica, "replica")
+
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(primary), ReplicaDSN(replica))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+
+	first := func(d *Database) string {
+		var user testUser
+		if err := d.SetDbModel(&testUser{}).SetScanModel(&user).First(); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		return user.Name
+	}
+
+	ctx := context.Background()
+	if got := first(NewDatabase(WithConnPool(pool.DB))); got != "replica" {
+		t.Errorf("expected read from replica, got %s", got)
+	}
+	if got := first(NewDatabase(WithConnPool(pool.DB), WithConnPrimary(true))); got != "primary" {
+		t.Errorf("expected SetPrimary to read from primary, got %s", got)
+	}
+	if got := first(NewDatabase(WithConnPool(pool.DB), WithConnContext(ContextWithPrimary(ctx)))); got != "primary" {
+		t.Errorf("expected ContextWithPrimary to read from primary, got %s", got)
+	}
+
+	err = pool.WithTx(ctx, func(tx *Database) error {
+		if got := first(tx); got != "primary" {
+			t.Errorf("expected transaction to use primary, got %s", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+}
+
+func TestRoundRobinPolicy(t *testing.T) {
+	pools := []gorm.ConnPool{&gorm.PreparedStmtDB{}, &gorm.PreparedStmtDB{}}
+	policy, err := newReplicaPolicy(ReplicaRoundRobin)
+	if err != nil {
+		t.Fatalf("newReplicaPolicy failed: %v", err)
+	}
+	if policy.Resolve(pools) != pools[0] || policy.Resolve(pools) != pools[1] || policy.Resolve(pools) != pools[0] {
+		t.Error("expected round robin selection")
+	}
+	if _, err := newReplicaPolicy("random"); err == nil {
+		t.Error("expected error for unsupported policy")
+	}
+}
+
+func TestReplicaPoolSettings(t *testing.T) {
+	dir := t.TempDir()
+	primaryFile, replicaFile := filepath.Join(dir, "primary.db"), filepath.Join(dir, "replica.db")
+	seedSQLite(t, primaryFile, "primary")
+	seedSQLite(t, replicaFile, "replica")
+
+	existing, err := NewDBPool(Driver(DriverSQLite), Name(primaryFile))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	primary, err := existing.DB.DB()
+	if err != nil {
+		t.Fatalf("DB failed: %v", err)
+	}
+	primary.SetMaxOpenConns(3)
+
+	// 外部传入的主库连接参数不被从库配置覆盖
+	pool, err := NewDBPool(DB(existing.DB), Driver(DriverSQLite), Name(primaryFile),
+		MaxOpenConn(7), ReplicaDSN(replicaFile))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	var replicas []*sql.DB
+	_ = pool.resolver.Call(func(connPool gorm.ConnPool) error {
+		if db, ok := connPool.(*sql.DB); ok && db != primary {
+			replicas = append(replicas, db)
+		}
+		return nil
+	})
+	if len(replicas) != 1 || replicas[0].Stats().MaxOpenConnections != 7 {
+		t.Fatalf("expected replica max open connections to be set, got %d replicas", len(replicas))
+	}
+	if primary.Stats().MaxOpenConnections != 3 {
+		t.Errorf("expected primary settings to be kept, got %d", primary.Stats().MaxOpenConnections)
+	}
+}
@@ -0,0 +1,301 @@
+package gormx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AggFunc 聚合函数
+type AggFunc string
+
+const (
+	AggCount AggFunc = "COUNT" // 计数
+	AggSum   AggFunc = "SUM"   // 求和
+	AggAvg   AggFunc = "AVG"   // 平均值
+	AggMin   AggFunc = "MIN"   // 最小值
+	AggMax   AggFunc = "MAX"   // 最大值
+)
+
+// BucketUnit 时间分桶粒度
+type BucketUnit string
+
+const (
+	BucketHour  BucketUnit = "hour"  // 按小时
+	BucketDay   BucketUnit = "day"   // 按天
+	BucketWeek  BucketUnit = "week"  // 按周，周一为每周第一天
+	BucketMonth BucketUnit = "month" // 按月
+)
+
+// bucketTemplates 各数据库的时间分桶表达式，? 为字段
+var bucketTemplates = map[string]map[BucketUnit]string{
+	DriverMySQL: {
+		BucketHour:  "DATE_FORMAT(?, '%Y-%m-%d %H:00:00')",
+		BucketDay:   "DATE_FORMAT(?, '%Y-%m-%d')",
+		BucketWeek:  "DATE_FORMAT(DATE_SUB(?, INTERVAL WEEKDAY(?) DAY), '%Y-%m-%d')",
+		BucketMonth: "DATE_FORMAT(?, '%Y-%m-01')",
+	},
+	DriverPostgres: {
+		BucketHour:  "date_trunc('hour', ?)",
+		BucketDay:   "date_trunc('day', ?)",
+		BucketWeek:  "date_trunc('week', ?)",
+		BucketMonth: "date_trunc('month', ?)",
+	},
+	DriverSQLite: {
+		BucketHour:  "strftime('%Y-%m-%d %H:00:00', ?)",
+		BucketDay:   "date(?)",
+		BucketWeek:  "date(?, 'weekday 0', '-6 days')",
+		BucketMonth: "strftime('%Y-%m-01', ?)",
+	},
+	DriverSQLServer: {
+		BucketHour:  "DATEADD(hour, DATEDIFF(hour, 0, ?), 0)",
+		BucketDay:   "CAST(? AS DATE)",
+		BucketWeek:  "CAST(DATEADD(day, -(DATEDIFF(day, 0, ?) % 7), ?) AS DATE)",
+		BucketMonth: "DATEADD(month, DATEDIFF(month, 0, ?), 0)",
+	},
+}
+
+// aliasPattern 结果字段别名
+var aliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reportColumn 报表的分组或聚合字段
+type reportColumn struct {
+	field string
+	alias string
+	agg   AggFunc
+	unit  BucketUnit
+}
+
+// Report 分组统计查询，沿用 Database 的查询条件、时间范围、过滤条件和分页，
+// 分桶字段在不同数据库返回时间或字符串，可使用 JsonTime 接收
+type Report struct {
+	d      *Database
+	groups []reportColumn
+	aggs   []reportColumn
+	having []Filter
+	sort   string
+}
+
+// Report 创建分组统计查询
+func (d *Database) Report() *Report {
+	return &Report{d: d}
+}
+
+// GroupBy 按字段分组，结果使用字段名作为别名
+func (r *Report) GroupBy(fields ...string) *Report {
+	for _, field := range fields {
+		r.groups = append(r.groups, reportColumn{field: field, alias: field})
+	}
+	return r
+}
+
+// Bucket 按时间字段分桶分组
+func (r *Report) Bucket(field string, unit BucketUnit, alias string) *Report {
+	r.groups = append(r.groups, reportColumn{field: field, alias: alias, unit: unit})
+	return r
+}
+
+// Aggregate 添加聚合字段，COUNT 的 field 为空时统计行数
+func (r *Report) Aggregate(agg AggFunc, field, alias string) *Report {
+	r.aggs = append(r.aggs, reportColumn{field: field, alias: alias, agg: agg})
+	return r
+}
+
+// Count 统计行数
+func (r *Report) Count(alias string) *Report {
+	return r.Aggregate(AggCount, "", alias)
+}
+
+// Sum 求和
+func (r *Report) Sum(field, alias string) *Report {
+	return r.Aggregate(AggSum, field, alias)
+}
+
+// Avg 平均值
+func (r *Report) Avg(field, alias string) *Report {
+	return r.Aggregate(AggAvg, field, alias)
+}
+
+// Min 最小值
+func (r *Report) Min(field, alias string) *Report {
+	return r.Aggregate(AggMin, field, alias)
+}
+
+// Max 最大值
+func (r *Report) Max(field, alias string) *Report {
+	return r.Aggregate(AggMax, field, alias)
+}
+
+// Having 分组过滤条件，字段可以是分组或聚合的别名
+func (r *Report) Having(filters ...Filter) *Report {
+	r.having = append(r.having, filters...)
+	return r
+}
+
+// Sort 排序规则，格式同 ParseSort，字段可以是分组或聚合的别名，默认按分组字段升序
+func (r *Report) Sort(spec string) *Report {
+	r.sort = spec
+	return r
+}
+
+// Scan 执行查询并将结果按别名扫描到 dest，如 *[]struct{Day JsonDate; Total int64}。
+// 设置了 Total 时同时统计分组数
+func (r *Report) Scan(dest interface{}) error {
+	if len(r.groups) == 0 && len(r.aggs) == 0 {
+		return fmt.Errorf("report requires at least one group or aggregate")
+	}
+
+	query, err := r.d.prepareQuery()
+	if err != nil {
+		return err
+	}
+	exprs, err := r.columns(query)
+	if err != nil {
+		return err
+	}
+	resolve := r.resolver(query, exprs)
+
+	selects := make([]string, 0, len(r.groups)+len(r.aggs))
+	groupBy := clause.GroupBy{}
+	for _, col := range r.groups {
+		selects = append(selects, exprs[col.alias]+" AS "+query.Statement.Quote(col.alias))
+		groupBy.Columns = append(groupBy.Columns, clause.Column{Name: exprs[col.alias], Raw: true})
+	}
+	for _, col := range r.aggs {
+		selects = append(selects, exprs[col.alias]+" AS "+query.Statement.Quote(col.alias))
+	}
+	if len(r.having) > 0 {
+		having, err := And(r.having...).build(resolve)
+		if err != nil {
+			return err
+		}
+		groupBy.Having = []clause.Expression{having}
+	}
+
+	query = query.Select(strings.Join(selects, ", "))
+	if len(groupBy.Columns) > 0 || len(groupBy.Having) > 0 {
+		query = query.Clauses(groupBy)
+	}
+
+	if r.d.opts.Total != nil {
+		if err := r.d.conn().Table("(?) AS report", query).Count(r.d.opts.Total).Error; err != nil {
+			return fmt.Errorf("failed to count total: %w", err)
+		}
+	}
+
+	order, err := r.order(resolve)
+	if err != nil {
+		return err
+	}
+	if order != nil {
+		query = query.Clauses(clause.OrderBy{Expression: order})
+	}
+	return r.d.applyPagination(query).Scan(dest).Error
+}
+
+// columns 生成分组和聚合字段的SQL表达式，按别名索引
+func (r *Report) columns(query *gorm.DB) (map[string]string, error) {
+	resolve := r.d.columnResolver(query)
+	exprs := make(map[string]string, len(r.groups)+len(r.aggs))
+	for _, col := range append(append([]reportColumn{}, r.groups...), r.aggs...) {
+		if !aliasPattern.MatchString(col.alias) {
+			return nil, fmt.Errorf("invalid report alias: %q", col.alias)
+		}
+		if _, ok := exprs[col.alias]; ok {
+			return nil, fmt.Errorf("duplicate report alias: %s", col.alias)
+		}
+
+		var column string
+		if col.field != "" {
+			name, err := resolve(col.field)
+			if err != nil {
+				return nil, err
+			}
+			column = query.Statement.Quote(name)
+		}
+
+		switch {
+		case col.agg != "":
+			expr, err := aggregateExpr(col.agg, column)
+			if err != nil {
+				return nil, err
+			}
+			exprs[col.alias] = expr
+		case col.unit != "":
+			expr, err := bucketExpr(query.Dialector.Name(), col.unit, column)
+			if err != nil {
+				return nil, err
+			}
+			exprs[col.alias] = expr
+		default:
+			exprs[col.alias] = column
+		}
+	}
+	return exprs, nil
+}
+
+// resolver HAVING 和排序的字段解析，别名解析为对应表达式，其余按字段白名单校验
+func (r *Report) resolver(query *gorm.DB, exprs map[string]string) func(string) (clause.Column, error) {
+	resolve := resolveColumn(r.d.columnResolver(query))
+	return func(name string) (clause.Column, error) {
+		if expr, ok := exprs[name]; ok {
+			return clause.Column{Name: expr, Raw: true}, nil
+		}
+		return resolve(name)
+	}
+}
+
+// order 生成排序表达式，未设置排序时按分组字段升序
+func (r *Report) order(resolve func(string) (clause.Column, error)) (clause.Expression, error) {
+	var sorts []Sort
+	if r.sort != "" {
+		var err error
+		if sorts, err = ParseSort(r.sort); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, col := range r.groups {
+			sorts = append(sorts, Sort{Column: col.alias})
+		}
+	}
+	if len(sorts) == 0 {
+		return nil, nil
+	}
+	return buildOrder(sorts, resolve)
+}
+
+// aggregateExpr 生成聚合表达式
+func aggregateExpr(agg AggFunc, column string) (string, error) {
+	switch agg {
+	case AggCount:
+		if column == "" {
+			column = "*"
+		}
+	case AggSum, AggAvg, AggMin, AggMax:
+		if column == "" {
+			return "", fmt.Errorf("%s requires a field", agg)
+		}
+	default:
+		return "", fmt.Errorf("unsupported aggregate: %s", agg)
+	}
+	return fmt.Sprintf("%s(%s)", agg, column), nil
+}
+
+// bucketExpr 生成时间分桶表达式
+func bucketExpr(dialect string, unit BucketUnit, column string) (string, error) {
+	if column == "" {
+		return "", fmt.Errorf("bucket requires a field")
+	}
+	templates, ok := bucketTemplates[dialect]
+	if !ok {
+		return "", fmt.Errorf("time bucket is not supported for %s", dialect)
+	}
+	template, ok := templates[unit]
+	if !ok {
+		return "", fmt.Errorf("unsupported bucket unit: %s", unit)
+	}
+	return strings.ReplaceAll(template, "?", column), nil
+}
@@ -0,0 +1,89 @@
+package gormx
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testSale struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	Shop      string    `json:"shop"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testSaleReport struct {
+	Day    JsonDate `json:"day"`
+	Shop   string   `json:"shop"`
+	Orders int64    `json:"orders"`
+	Amount float64  `json:"amount"`
+	Top    float64  `json:"top"`
+}
+
+func TestReport(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "report.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testSale{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	day1 := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	sales := []testSale{
+		{Shop: "a", Amount: 10, CreatedAt: day1},
+		{Shop: "a", Amount: 20, CreatedAt: day1.Add(time.Hour)},
+		{Shop: "b", Amount: 5, CreatedAt: day1},
+		{Shop: "a", Amount: 7, CreatedAt: day2},
+		{Shop: "a", Amount: 100, CreatedAt: day2.Add(30 * 24 * time.Hour)},
+	}
+	if err := db.Create(&sales).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var rows []testSaleReport
+	var total int64
+	err = NewDatabase(WithConnPool(db), WithConnDbModel(&testSale{}), WithConnTotal(&total),
+		WithConnStartTime("2024-05-01"), WithConnEndTime("2024-05-31")).
+		Report().
+		Bucket("created_at", BucketDay, "day").
+		GroupBy("shop").
+		Count("orders").
+		Sum("amount", "amount").
+		Max("amount", "top").
+		Having(Gt("orders", 0)).
+		Sort("day,-amount").
+		Scan(&rows)
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if total != 3 || len(rows) != 3 {
+		t.Fatalf("expected 3 groups, got %d, %+v", total, rows)
+	}
+	first := rows[0]
+	if first.Day.Format("2006-01-02") != "2024-05-06" || first.Shop != "a" || first.Orders != 2 || first.Amount != 30 || first.Top != 20 {
+		t.Errorf("unexpected first row: %+v", first)
+	}
+
+	var months []struct {
+		Month  JsonTime
+		Orders int64
+	}
+	err = NewDatabase(WithConnPool(db), WithConnDbModel(&testSale{})).Report().
+		Bucket("created_at", BucketMonth, "month").Count("orders").Having(Gte("orders", 2)).Scan(&months)
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if len(months) != 1 || months[0].Month.Format("2006-01-02") != "2024-05-01" || months[0].Orders != 4 {
+		t.Errorf("unexpected month rows: %+v", months)
+	}
+
+	err = NewDatabase(WithConnPool(db), WithConnDbModel(&testSale{})).Report().GroupBy("password").Count("n").Scan(&months)
+	if !errors.Is(err, ErrFieldNotAllowed) {
+		t.Errorf("expected ErrFieldNotAllowed, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package gormx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository 泛型仓储，在Database之上提供类型化的增删改查
+type Repository[T any] struct {
+	db   *gorm.DB
+	opts []ConnectionOption
+}
+
+// NewRepository 创建泛型仓储，opts 作为每次查询的默认选项(如 WithConnLike、WithConnIn)
+func NewRepository[T any](db *gorm.DB, opts ...ConnectionOption) *Repository[T] {
+	return &Repository[T]{db: db, opts: opts}
+}
+
+// DB 获取底层连接
+func (r *Repository[T]) DB() *gorm.DB {
+	return r.db
+}
+
+// database 合并默认选项和调用选项，生成绑定模型T的Database
+func (r *Repository[T]) database(ctx context.Context, opts ...ConnectionOption) *Database {
+	options := make([]ConnectionOption, 0, len(r.opts)+len(opts)+3)
+	options = append(options, r.opts...)
+	options = append(options, opts...)
+	options = append(options, WithConnPool(r.db), WithConnContext(ctx), WithConnDbModel(new(T)))
+	return NewDatabase(options...)
+}
+
+// Get 根据主键获取记录，默认选项(Primary、Unscoped、缓存等)同样生效，不存在时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) Get(ctx context.Context, id interface{}, opts ...ConnectionOption) (*T, error) {
+	value := new(T)
+	err := r.database(ctx, opts...).SetScanModel(value).where(primaryKeyEq(id)).First()
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// List 按条件查询列表并返回总数，条件、Like、In、时间范围和分页沿用Database的语义
+func (r *Repository[T]) List(ctx context.Context, opts ...ConnectionOption) ([]T, int64, error) {
+	var (
+		items []T
+		total int64
+	)
+	err := r.database(ctx, opts...).SetScanModel(&items).SetTotal(&total).Query()
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Create 新增记录
+func (r *Repository[T]) Create(ctx context.Context, value *T) error {
+	return r.database(ctx).Create(value)
+}
+
+// BulkCreate 分批新增记录，可通过 WithConnBatchSize、WithConnConflictKeys、WithConnUpdateExcept 设置批量和冲突更新
+func (r *Repository[T]) BulkCreate(ctx context.Context, values []T, opts ...ConnectionOption) error {
+	return r.database(ctx, opts...).BulkCreate(values)
+}
+
+// Stream 按主键顺序分批读取符合条件的记录，每批调用一次 fn，fn 返回错误时停止。
+// 批次切片会被下一批复用，需要保留时请复制
+func (r *Repository[T]) Stream(ctx context.Context, batchSize int, fn func([]T) error, opts ...ConnectionOption) error {
+	query, err := r.database(ctx, opts...).prepareQuery()
+	if err != nil {
+		return err
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	var batch []T
+	return query.FindInBatches(&batch, batchSize, func(*gorm.DB, int) error {
+		return fn(batch)
+	}).Error
+}
+
+// Update 按主键更新记录的全部可更新字段(含零值)，创建时间由数据库保留、更新时间自动填充，
+// 主键为空时返回 ErrMissingPrimaryKey，不会新增记录
+func (r *Repository[T]) Update(ctx context.Context, value *T, opts ...ConnectionOption) error {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(value); err != nil {
+		return fmt.Errorf("failed to parse model: %w", err)
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return ErrMissingPrimaryKey
+	}
+	id, isZero := field.ValueOf(ctx, reflect.ValueOf(value).Elem())
+	if isZero {
+		return ErrMissingPrimaryKey
+	}
+
+	columns := make([]string, 0, len(stmt.Schema.Fields))
+	for _, f := range stmt.Schema.Fields {
+		if f.DBName == "" || f.PrimaryKey || !f.Updatable || f.AutoCreateTime > 0 || f.AutoUpdateTime > 0 {
+			continue
+		}
+		columns = append(columns, f.DBName)
+	}
+	return r.database(ctx, opts...).SetDbModel(value).SetValues(columns).where(primaryKeyEq(id)).Update()
+}
+
+// Delete 根据主键删除记录，默认选项同样生效，如 WithConnUnscoped 时物理删除
+func (r *Repository[T]) Delete(ctx context.Context, id interface{}, opts ...ConnectionOption) error {
+	return r.database(ctx, opts...).where(primaryKeyEq(id)).Delete()
+}
+
+// primaryKeyEq 主键等于 id 的条件
+func primaryKeyEq(id interface{}) clause.Expression {
+	return clause.Eq{Column: clause.PrimaryColumn, Value: id}
+}
+
+// Exists 判断是否存在符合条件的记录
+func (r *Repository[T]) Exists(ctx context.Context, opts ...ConnectionOption) (bool, error) {
+	total, err := r.Count(ctx, opts...)
+	if err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// Count 统计符合条件的记录数
+func (r *Repository[T]) Count(ctx context.Context, opts ...ConnectionOption) (int64, error) {
+	var total int64
+	if err := r.database(ctx, opts...).SetTotal(&total).Count(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
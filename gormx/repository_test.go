@@ -0,0 +1,106 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hchicken/pkg-go/gormx"
+	"github.com/hchicken/pkg-go/gormx/gormxtest"
+	"gorm.io/gorm"
+)
+
+type testArticle struct {
+	ID        int64          `json:"id" gorm:"primaryKey"`
+	Title     string         `json:"title"`
+	Views     int            `json:"views"`
+	Sort      int            `json:"sort"`
+	UpdatedBy string         `json:"updated_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at"`
+}
+
+func TestRepository(t *testing.T) {
+	db := gormxtest.New(t, gormxtest.Models(&testArticle{}))
+	ctx := db.Begin(t)
+	repo := gormx.NewRepository[testArticle](db.GetConn())
+
+	article := &testArticle{Title: "hello", Views: 3}
+	if err := repo.Create(ctx, article); err != nil || article.ID == 0 {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.Create(ctx, &testArticle{Title: "world"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := repo.Get(ctx, article.ID)
+	if err != nil || got.Title != "hello" {
+		t.Fatalf("Get failed: %+v, %v", got, err)
+	}
+	if _, err := repo.Get(ctx, 404); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	got.Title, got.Views, got.Sort = "updated", 0, 2
+	if err := repo.Update(ctx, got, gormx.WithConnOperator("alice")); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := repo.Get(ctx, article.ID); got.Title != "updated" || got.Views != 0 || got.Sort != 2 ||
+		got.UpdatedBy != "alice" || got.CreatedAt.IsZero() {
+		t.Errorf("expected zero value, sort and operator written, created_at kept, got %+v", got)
+	}
+	if err := repo.Update(ctx, &testArticle{Title: "new"}); !errors.Is(err, gormx.ErrMissingPrimaryKey) {
+		t.Errorf("expected ErrMissingPrimaryKey, got %v", err)
+	}
+
+	items, total, err := repo.List(ctx, gormx.WithConnConditions(map[string]interface{}{"title": "updated"}))
+	if err != nil || total != 1 || len(items) != 1 || items[0].ID != article.ID {
+		t.Errorf("unexpected List result: %+v %d %v", items, total, err)
+	}
+	if ok, err := repo.Exists(ctx, gormx.WithConnConditions(map[string]interface{}{"title": "world"})); err != nil || !ok {
+		t.Errorf("expected record to exist, got %v, %v", ok, err)
+	}
+
+	if err := repo.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if total, err := repo.Count(ctx); err != nil || total != 1 {
+		t.Errorf("expected 1 record after delete, got %d, %v", total, err)
+	}
+	if _, err := repo.Get(ctx, article.ID, gormx.WithConnUnscoped(true)); err != nil {
+		t.Errorf("expected soft deleted record with Unscoped, got %v", err)
+	}
+	if err := repo.Delete(ctx, article.ID, gormx.WithConnUnscoped(true)); err != nil {
+		t.Fatalf("Unscoped Delete failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, article.ID, gormx.WithConnUnscoped(true)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected record to be removed, got %v", err)
+	}
+}
+
+func TestRepositoryCanceled(t *testing.T) {
+	db := gormxtest.New(t, gormxtest.Models(&testArticle{}))
+	repo := gormx.NewRepository[testArticle](db.GetConn())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	article := &testArticle{ID: 1, Title: "hello"}
+	_, getErr := repo.Get(ctx, 1)
+	_, _, listErr := repo.List(ctx)
+	_, existsErr := repo.Exists(ctx)
+	_, countErr := repo.Count(ctx)
+	for name, err := range map[string]error{
+		"Create": repo.Create(ctx, article),
+		"Update": repo.Update(ctx, article),
+		"Delete": repo.Delete(ctx, 1),
+		"Get":    getErr,
+		"List":   listErr,
+		"Exists": existsErr,
+		"Count":  countErr,
+	} {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package gormx
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NullsOrder 空值排序位置
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota // 数据库默认
+	NullsFirst                     // 空值在前
+	NullsLast                      // 空值在后
+)
+
+// Sort 排序字段
+type Sort struct {
+	Column string     // 字段
+	Desc   bool       // 是否降序
+	Nulls  NullsOrder // 空值位置
+}
+
+// ParseSort 解析排序规则，多个字段用逗号分隔，- 前缀表示降序，
+// 可追加 :nulls_first 或 :nulls_last，如 "-created_at,name:nulls_last"
+func ParseSort(spec string) ([]Sort, error) {
+	var sorts []Sort
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var sort Sort
+		column, nulls, _ := strings.Cut(item, ":")
+		switch strings.ToLower(strings.TrimSpace(nulls)) {
+		case "":
+		case "nulls_first":
+			sort.Nulls = NullsFirst
+		case "nulls_last":
+			sort.Nulls = NullsLast
+		default:
+			return nil, fmt.Errorf("%w nulls order: %s", ErrInvalidSort, nulls)
+		}
+
+		column = strings.TrimSpace(column)
+		switch {
+		case strings.HasPrefix(column, "-"):
+			sort.Desc = true
+			column = column[1:]
+		case strings.HasPrefix(column, "+"):
+			column = column[1:]
+		}
+		if column == "" {
+			return nil, fmt.Errorf("%w field: %s", ErrInvalidSort, item)
+		}
+		sort.Column = column
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+// buildOrder 校验排序字段并生成 ORDER BY 表达式。
+// NULLS FIRST/LAST 使用 CASE WHEN 排序模拟，兼容 MySQL、Postgres、SQLite 和 SQL Server
+func buildOrder(sorts []Sort, resolve func(string) (clause.Column, error)) (clause.Expression, error) {
+	parts := make([]string, 0, len(sorts))
+	vars := make([]interface{}, 0, len(sorts))
+	for _, sort := range sorts {
+		column, err := resolve(sort.Column)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort field: %w", err)
+		}
+
+		switch sort.Nulls {
+		case NullsFirst:
+			parts = append(parts, "CASE WHEN ? IS NULL THEN 0 ELSE 1 END")
+			vars = append(vars, column)
+		case NullsLast:
+			parts = append(parts, "CASE WHEN ? IS NULL THEN 1 ELSE 0 END")
+			vars = append(vars, column)
+		}
+
+		if sort.Desc {
+			parts = append(parts, "? DESC")
+		} else {
+			parts = append(parts, "?")
+		}
+		vars = append(vars, column)
+	}
+	return clause.Expr{SQL: strings.Join(parts, ","), Vars: vars, WithoutParentheses: true}, nil
+}
+
+// applySort 按 Sort 排序规则排序
+func (d *Database) applySort(query *gorm.DB) (*gorm.DB, error) {
+	sorts, err := ParseSort(d.opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	if len(sorts) == 0 {
+		return query, nil
+	}
+	expr, err := buildOrder(sorts, resolveColumn(d.sortResolver(query)))
+	if err != nil {
+		return nil, err
+	}
+	return query.Clauses(clause.OrderBy{Expression: expr}), nil
+}
@@ -0,0 +1,104 @@
+package gormx
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	sorts, err := ParseSort("-created_at, name:nulls_last,+age")
+	if err != nil {
+		t.Fatalf("ParseSort failed: %v", err)
+	}
+	want := []Sort{
+		{Column: "created_at", Desc: true},
+		{Column: "name", Nulls: NullsLast},
+		{Column: "age"},
+	}
+	if len(sorts) != len(want) {
+		t.Fatalf("expected %d sorts, got %d", len(want), len(sorts))
+	}
+	for i := range want {
+		if sorts[i] != want[i] {
+			t.Errorf("sort %d: expected %+v, got %+v", i, want[i], sorts[i])
+		}
+	}
+
+	if _, err := ParseSort("name:nulls_middle"); err == nil {
+		t.Error("expected error for invalid nulls order")
+	}
+	if _, err := ParseSort("-"); err == nil {
+		t.Error("expected error for empty column")
+	}
+}
+
+func TestDatabaseSort(t *testing.T) {
+	db := dryRunDB(t)
+	sql, err := querySQL(t, NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}),
+		WithConnSort("-age,email:nulls_first")))
+	if err != nil {
+		t.Fatalf("prepareQuery failed: %v", err)
+	}
+	if want := "ORDER BY `age` DESC,CASE WHEN `email` IS NULL THEN 0 ELSE 1 END,`email`"; !strings.Contains(sql, want) {
+		t.Errorf("expected %q in %s", want, sql)
+	}
+
+	_, err = querySQL(t, NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}),
+		WithConnSort("(select 1)")))
+	if !errors.Is(err, ErrFieldNotAllowed) {
+		t.Errorf("expected ErrFieldNotAllowed, got %v", err)
+	}
+}
+
+func TestDatabaseSortFields(t *testing.T) {
+	db := dryRunDB(t)
+	base := func(opts ...ConnectionOption) *Database {
+		opts = append([]ConnectionOption{WithConnPool(db), WithConnDbModel(&testUser{}),
+			WithConnAllowedFields([]string{"name", "email"})}, opts...)
+		return NewDatabase(opts...)
+	}
+
+	if _, err := querySQL(t, base(WithConnSort("age"))); !errors.Is(err, ErrFieldNotAllowed) {
+		t.Errorf("expected AllowedFields to restrict sort without SortFields, got %v", err)
+	}
+	sql, err := querySQL(t, base(WithConnSortFields([]string{"age"}), WithConnSort("-age")))
+	if err != nil || !strings.Contains(sql, "ORDER BY `age` DESC") {
+		t.Errorf("expected sort by age, got %s, %v", sql, err)
+	}
+	if _, err := querySQL(t, base(WithConnSortFields([]string{"age"}), WithConnSort("name"))); !errors.Is(err, ErrFieldNotAllowed) {
+		t.Errorf("expected SortFields to restrict sort, got %v", err)
+	}
+}
+
+func TestDatabaseSortNulls(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "sort.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	db.Exec("INSERT INTO test_users (name, email) VALUES ('a', 'x'), ('b', NULL), ('c', 'y')")
+
+	names := func(spec string) string {
+		var users []testUser
+		if err := NewDatabase(WithConnPool(db), WithConnDbModel(&testUser{}), WithConnScanModel(&users),
+			WithConnSort(spec)).Query(); err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		var b strings.Builder
+		for _, user := range users {
+			b.WriteString(user.Name)
+		}
+		return b.String()
+	}
+	if got := names("email:nulls_first"); got != "bac" {
+		t.Errorf("expected nulls first, got %s", got)
+	}
+	if got := names("-email:nulls_last"); got != "cab" {
+		t.Errorf("expected nulls last, got %s", got)
+	}
+}
@@ -0,0 +1,266 @@
+package gormx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantPattern schema路由时租户名称允许的字符
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// tenantKey 上下文中租户的key
+type tenantKey struct{}
+
+// skipTenantKey 上下文中跳过租户隔离的key
+type skipTenantKey struct{}
+
+// ContextWithTenant 在上下文中设置租户
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext 获取上下文中的租户
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// SkipTenant 使用该上下文的语句不做租户隔离，用于后台任务等跨租户操作
+func SkipTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipTenantKey{}, true)
+}
+
+// isSkipTenant 上下文是否跳过租户隔离
+func isSkipTenant(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipTenantKey{}).(bool)
+	return skip
+}
+
+// tenantOptions 租户插件配置
+type tenantOptions struct {
+	field  string
+	strict bool
+	schema string
+}
+
+// TenantOption 租户插件配置项
+type TenantOption func(*tenantOptions)
+
+// TenantField 设置租户字段，默认 tenant_id
+func TenantField(field string) TenantOption {
+	return func(o *tenantOptions) {
+		o.field = field
+	}
+}
+
+// TenantStrict 严格模式，上下文中没有租户时返回 ErrTenantRequired
+func TenantStrict(b bool) TenantOption {
+	return func(o *tenantOptions) {
+		o.strict = b
+	}
+}
+
+// TenantSchema 按租户分schema，表名加上 fmt.Sprintf(format, tenant) 作为schema前缀，
+// 如 "tenant_%s" 时查询 tenant_1.users，MySQL 中schema即数据库。设置后不再按租户字段过滤
+func TenantSchema(format string) TenantOption {
+	return func(o *tenantOptions) {
+		o.schema = format
+	}
+}
+
+// TenantPlugin 租户隔离插件：查询、更新、删除自动追加 tenant_id = ? 条件，创建时填充租户字段，
+// 与上下文租户不一致时返回 ErrTenantMismatch，更新时忽略租户字段；
+// 设置 TenantSchema 时改为按租户路由到对应schema。Raw/Exec 执行的原生SQL不做处理
+type TenantPlugin struct {
+	opts tenantOptions
+}
+
+// NewTenantPlugin 创建租户插件，通过 db.Use 或 gormx.Plugin 注册
+func NewTenantPlugin(opts ...TenantOption) *TenantPlugin {
+	o := tenantOptions{field: "tenant_id"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &TenantPlugin{opts: o}
+}
+
+// Name 实现 gorm.Plugin
+func (p *TenantPlugin) Name() string {
+	return "gormx:tenant"
+}
+
+// Initialize 实现 gorm.Plugin，注册回调
+func (p *TenantPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	errs := []error{
+		cb.Create().Before("gorm:create").Register("gormx:tenant_create", p.create),
+		cb.Query().Before("gorm:query").Register("gormx:tenant_query", p.scope),
+		cb.Row().Before("gorm:row").Register("gormx:tenant_row", p.scope),
+		cb.Update().Before("gorm:update").Register("gormx:tenant_update", p.update),
+		cb.Delete().Before("gorm:delete").Register("gormx:tenant_delete", p.scope),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tenant 获取当前语句的租户，skip 为 true 时不做隔离
+func (p *TenantPlugin) tenant(db *gorm.DB) (tenant string, skip bool) {
+	ctx := db.Statement.Context
+	if db.Error != nil || db.Statement.Schema == nil || ctx == nil || isSkipTenant(ctx) {
+		return "", true
+	}
+	if p.opts.schema == "" && db.Statement.Schema.LookUpField(p.opts.field) == nil {
+		return "", true
+	}
+
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		if p.opts.strict {
+			db.AddError(fmt.Errorf("%w: %s", ErrTenantRequired, db.Statement.Table))
+		}
+		return "", true
+	}
+	return tenant, false
+}
+
+// scope 查询、更新、删除时按租户过滤或路由
+func (p *TenantPlugin) scope(db *gorm.DB) {
+	tenant, skip := p.tenant(db)
+	if skip {
+		return
+	}
+	if p.opts.schema != "" {
+		p.route(db, tenant)
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(p.opts.field)
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenant},
+	}})
+}
+
+// update 更新时按租户过滤，并忽略租户字段，不允许修改记录所属租户
+func (p *TenantPlugin) update(db *gorm.DB) {
+	p.scope(db)
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	if field := db.Statement.Schema.LookUpField(p.opts.field); field != nil {
+		db.Statement.Omits = append(db.Statement.Omits, field.DBName)
+	}
+}
+
+// route 将表路由到租户schema，租户名称必须是合法标识符，schema和表名由数据库方言转义
+func (p *TenantPlugin) route(db *gorm.DB, tenant string) {
+	stmt := db.Statement
+	if stmt.TableExpr != nil || stmt.Table == "" || strings.Contains(stmt.Table, ".") {
+		return
+	}
+	if !tenantPattern.MatchString(tenant) {
+		db.AddError(fmt.Errorf("%w: %q", ErrInvalidTenant, tenant))
+		return
+	}
+	stmt.TableExpr = &clause.Expr{SQL: "?.?", Vars: []interface{}{
+		clause.Table{Name: fmt.Sprintf(p.opts.schema, tenant)},
+		clause.Table{Name: stmt.Table},
+	}}
+}
+
+// create 创建时填充为空的租户字段，已填写其他租户时返回 ErrTenantMismatch
+func (p *TenantPlugin) create(db *gorm.DB) {
+	tenant, skip := p.tenant(db)
+	if skip {
+		return
+	}
+	if p.opts.schema != "" {
+		p.route(db, tenant)
+	}
+
+	field := db.Statement.Schema.LookUpField(p.opts.field)
+	if field == nil {
+		return
+	}
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]interface{}:
+		setTenantMap(db, field, dest, tenant)
+		return
+	case []map[string]interface{}:
+		for _, m := range dest {
+			setTenantMap(db, field, m, tenant)
+		}
+		return
+	}
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			setTenant(db, field, reflect.Indirect(rv.Index(i)), tenant)
+		}
+	case reflect.Struct:
+		setTenant(db, field, rv, tenant)
+	}
+}
+
+// setTenant 租户字段为空时填充，不一致时返回 ErrTenantMismatch
+func setTenant(db *gorm.DB, field *schema.Field, rv reflect.Value, tenant string) {
+	value, zero := field.ValueOf(db.Statement.Context, rv)
+	if zero {
+		db.AddError(field.Set(db.Statement.Context, rv, tenant))
+		return
+	}
+	if fmt.Sprint(value) != tenant {
+		db.AddError(fmt.Errorf("%w: %v", ErrTenantMismatch, value))
+	}
+}
+
+// setTenantMap map创建时填充租户字段，不一致时返回 ErrTenantMismatch
+func setTenantMap(db *gorm.DB, field *schema.Field, m map[string]interface{}, tenant string) {
+	for _, key := range []string{field.DBName, field.Name} {
+		if value, ok := m[key]; ok && value != nil && fmt.Sprint(value) != "" {
+			if fmt.Sprint(value) != tenant {
+				db.AddError(fmt.Errorf("%w: %v", ErrTenantMismatch, value))
+			}
+			return
+		}
+	}
+	m[field.DBName] = tenant
+}
+
+// Tenant 获取上下文中租户的连接池，租户连接池首次使用时创建。
+// 未设置 TenantDatabase 时返回自身
+func (c *DBPool) Tenant(ctx context.Context) (*DBPool, error) {
+	if c.opts.tenantDatabase == nil {
+		return c, nil
+	}
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrTenantRequired
+	}
+
+	c.tenantMu.Lock()
+	defer c.tenantMu.Unlock()
+	if pool, ok := c.tenants[tenant]; ok {
+		return pool, nil
+	}
+	pool, err := NewDBPool(c.opts.tenantDatabase(tenant)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect tenant %s: %w", tenant, err)
+	}
+	if c.tenants == nil {
+		c.tenants = make(map[string]*DBPool)
+	}
+	c.tenants[tenant] = pool
+	return pool, nil
+}
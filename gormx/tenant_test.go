@@ -0,0 +1,130 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+type testOrder struct {
+	ID       int64  `json:"id" gorm:"primaryKey"`
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+}
+
+func TestTenantPlugin(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "tenant.db")),
+		Plugin(NewTenantPlugin(TenantStrict(true))))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testOrder{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	ctxA := ContextWithTenant(context.Background(), "a")
+	ctxB := ContextWithTenant(context.Background(), "b")
+	for _, ctx := range []context.Context{ctxA, ctxA, ctxB} {
+		if err := db.WithContext(ctx).Create(&testOrder{Name: "order"}).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	var orders []testOrder
+	if err := NewDatabase(WithConnPool(db), WithConnContext(ctxA), WithConnDbModel(&testOrder{}),
+		WithConnScanModel(&orders)).Query(); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(orders) != 2 || orders[0].TenantID != "a" {
+		t.Errorf("expected 2 orders of tenant a, got %+v", orders)
+	}
+
+	if err := NewDatabase(WithConnPool(db), WithConnContext(ctxB), WithConnDbModel(&testOrder{}),
+		WithConnConditions(map[string]interface{}{"id": orders[0].ID})).Delete(); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var total int64
+	if err := db.WithContext(SkipTenant(context.Background())).Model(&testOrder{}).Count(&total).Error; err != nil || total != 3 {
+		t.Errorf("expected cross-tenant delete to be scoped out, got %d, %v", total, err)
+	}
+
+	if err := db.WithContext(context.Background()).Find(&orders).Error; !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("expected ErrTenantRequired in strict mode, got %v", err)
+	}
+
+	if err := db.WithContext(ctxA).Create(&testOrder{TenantID: "b", Name: "forged"}).Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Errorf("expected ErrTenantMismatch for another tenant, got %v", err)
+	}
+	if err := db.WithContext(ctxA).Create(&[]testOrder{{TenantID: "a"}, {TenantID: "b"}}).Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Errorf("expected ErrTenantMismatch in batch create, got %v", err)
+	}
+	if err := db.WithContext(ctxA).Model(&testOrder{}).Where("id = ?", orders[1].ID).
+		Updates(map[string]interface{}{"tenant_id": "b", "name": "moved"}).Error; err != nil {
+		t.Fatalf("Updates failed: %v", err)
+	}
+	var moved testOrder
+	if err := db.WithContext(SkipTenant(context.Background())).First(&moved, orders[1].ID).Error; err != nil || moved.TenantID != "a" || moved.Name != "moved" {
+		t.Errorf("expected tenant_id to be kept on update, got %+v, %v", moved, err)
+	}
+}
+
+func TestTenantSchema(t *testing.T) {
+	dir := t.TempDir()
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(dir, "main.db")), MaxOpenConn(1), MaxIdleConn(1),
+		Plugin(NewTenantPlugin(TenantSchema("tenant_%s"))))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.Exec("ATTACH DATABASE ? AS tenant_a", filepath.Join(dir, "a.db")).Error; err != nil {
+		t.Fatalf("ATTACH failed: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE tenant_a.test_orders (id INTEGER PRIMARY KEY, tenant_id TEXT, name TEXT)").Error; err != nil {
+		t.Fatalf("CREATE TABLE failed: %v", err)
+	}
+
+	ctx := ContextWithTenant(context.Background(), "a")
+	if err := db.WithContext(ctx).Create(&testOrder{Name: "order"}).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var total int64
+	if err := db.Raw("SELECT COUNT(*) FROM tenant_a.test_orders").Scan(&total).Error; err != nil || total != 1 {
+		t.Errorf("expected order in tenant schema, got %d, %v", total, err)
+	}
+	if err := db.WithContext(ctx).Model(&testOrder{}).Count(&total).Error; err != nil || total != 1 {
+		t.Errorf("expected query routed to tenant schema, got %d, %v", total, err)
+	}
+
+	evil := ContextWithTenant(context.Background(), "a.test_orders; DROP TABLE test_orders; --")
+	if err := db.WithContext(evil).Model(&testOrder{}).Count(&total).Error; !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected ErrInvalidTenant, got %v", err)
+	}
+}
+
+func TestTenantDatabase(t *testing.T) {
+	dir := t.TempDir()
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(dir, "main.db")),
+		TenantDatabase(func(tenant string) []Option {
+			return []Option{Driver(DriverSQLite), Name(filepath.Join(dir, tenant+".db"))}
+		}))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	if _, err := pool.Tenant(context.Background()); !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("expected ErrTenantRequired, got %v", err)
+	}
+	ctx := ContextWithTenant(context.Background(), "a")
+	a, err := pool.Tenant(ctx)
+	if err != nil {
+		t.Fatalf("Tenant failed: %v", err)
+	}
+	again, _ := pool.Tenant(ctx)
+	b, _ := pool.Tenant(ContextWithTenant(context.Background(), "b"))
+	if a != again || a == b || a == pool {
+		t.Error("expected one cached pool per tenant")
+	}
+}
@@ -0,0 +1,71 @@
+package gormx
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// txKey 上下文中事务的key
+type txKey struct{}
+
+// ContextWithTx 将事务放入上下文，使用该上下文的Database和Repository会在事务中执行
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext 从上下文获取事务
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// dbWithContext 绑定上下文，上下文中有事务时优先使用事务
+func dbWithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		db = tx
+	}
+	db = db.WithContext(ctx)
+	if isPrimary(ctx) {
+		db = db.Clauses(dbresolver.Write)
+	}
+	return db
+}
+
+// transaction 开启事务执行fn，fn返回nil时提交，返回错误或panic时回滚。
+// 上下文中已有事务时使用savepoint嵌套，最外层事务提交后使事务中写入的表的查询缓存失效
+func transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context, tx *gorm.DB) error) error {
+	var pending *txCache
+	if _, ok := TxFromContext(ctx); !ok {
+		pending = &txCache{}
+		ctx = context.WithValue(ctx, txCacheKey{}, pending)
+	}
+	err := dbWithContext(ctx, db).Transaction(func(tx *gorm.DB) error {
+		return fn(ContextWithTx(ctx, tx), tx)
+	})
+	if err == nil && pending != nil {
+		pending.flush(ctx, db.Logger)
+	}
+	return err
+}
+
+// WithTx 在事务中执行fn，tx 绑定了携带事务的上下文
+func (c *DBPool) WithTx(ctx context.Context, fn func(tx *Database) error) error {
+	if c.DB == nil {
+		return ErrNotInitialized
+	}
+	return transaction(ctx, c.DB, func(ctx context.Context, tx *gorm.DB) error {
+		return fn(NewDatabase(WithConnPool(tx), WithConnContext(ctx)))
+	})
+}
+
+// WithTx 在事务中执行fn，tx 复制当前Database的配置
+func (d *Database) WithTx(ctx context.Context, fn func(tx *Database) error) error {
+	return transaction(ctx, d.db, func(ctx context.Context, tx *gorm.DB) error {
+		txDatabase := &Database{db: tx, opts: d.opts}
+		txDatabase.opts.Pool = tx
+		txDatabase.opts.Ctx = ctx
+		return fn(txDatabase)
+	})
+}
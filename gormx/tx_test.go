@@ -0,0 +1,87 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithTx(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "tx.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	if err := pool.GetConn().AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	ctx := context.Background()
+	repo := NewRepository[testUser](pool.GetConn())
+	count := func(name string) int64 {
+		total, err := repo.Count(ctx, WithConnConditions(map[string]interface{}{"name": name}))
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		return total
+	}
+	errRollback := errors.New("rollback")
+
+	// 返回错误时回滚，上下文携带事务
+	err = pool.WithTx(ctx, func(tx *Database) error {
+		txCtx := tx.Context()
+		if _, ok := TxFromContext(txCtx); !ok {
+			t.Error("expected transaction in context")
+		}
+		if err := repo.Create(txCtx, &testUser{Name: "error"}); err != nil {
+			return err
+		}
+		if total, _ := repo.Count(txCtx, WithConnConditions(map[string]interface{}{"name": "error"})); total != 1 {
+			t.Errorf("expected record to be visible in transaction, got %d", total)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) || count("error") != 0 {
+		t.Errorf("expected rollback on error, got %v", err)
+	}
+
+	// panic 时回滚
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic to be propagated")
+			}
+		}()
+		_ = pool.WithTx(ctx, func(tx *Database) error {
+			if err := repo.Create(tx.Context(), &testUser{Name: "panic"}); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+	if count("panic") != 0 {
+		t.Error("expected rollback on panic")
+	}
+
+	// 嵌套事务使用savepoint，内层回滚不影响外层
+	err = pool.WithTx(ctx, func(tx *Database) error {
+		if err := repo.Create(tx.Context(), &testUser{Name: "outer"}); err != nil {
+			return err
+		}
+		inner := tx.WithTx(tx.Context(), func(tx *Database) error {
+			if err := repo.Create(tx.Context(), &testUser{Name: "inner"}); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(inner, errRollback) {
+			t.Errorf("expected inner error, got %v", inner)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if count("outer") != 1 || count("inner") != 0 {
+		t.Errorf("expected outer committed and inner rolled back, got %d %d", count("outer"), count("inner"))
+	}
+}
@@ -0,0 +1,368 @@
+package gormx
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	timeLayout   = time.DateTime // JsonTime 的json格式
+	dateLayout   = time.DateOnly // JsonDate 的json格式
+	timeLocation *time.Location  // 序列化和解析使用的时区，为空时序列化保持原时区、解析使用 time.Local
+)
+
+// scanLayouts 驱动返回字符串时尝试的时间格式，sqlite 默认使用第一种
+var scanLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	time.DateTime,
+	time.DateOnly,
+}
+
+// SetTimeLayout 设置 JsonTime 的json格式，默认 time.DateTime，需在程序启动时设置
+func SetTimeLayout(layout string) {
+	timeLayout = layout
+}
+
+// SetDateLayout 设置 JsonDate 的json格式，默认 time.DateOnly，需在程序启动时设置
+func SetDateLayout(layout string) {
+	dateLayout = layout
+}
+
+// SetTimeLocation 设置 JsonTime、JsonDate 序列化和解析使用的时区，需在程序启动时设置
+func SetTimeLocation(loc *time.Location) {
+	timeLocation = loc
+}
+
+// formatTime 按格式和时区格式化时间
+func formatTime(t time.Time, layout string) string {
+	if timeLocation != nil {
+		t = t.In(timeLocation)
+	}
+	return t.Format(layout)
+}
+
+// marshalTime 序列化为json字符串
+func marshalTime(t time.Time, layout string) []byte {
+	return []byte(strconv.Quote(formatTime(t, layout)))
+}
+
+// unmarshalTime 解析json字符串，null 和空字符串返回 valid=false
+func unmarshalTime(b []byte, layout string) (t time.Time, valid bool, err error) {
+	if bytes.Equal(b, []byte("null")) {
+		return time.Time{}, false, nil
+	}
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %s: %w", b, err)
+	}
+	if s == "" {
+		return time.Time{}, false, nil
+	}
+
+	loc := timeLocation
+	if loc == nil {
+		loc = time.Local
+	}
+	t, err = time.ParseInLocation(layout, s, loc)
+	return t, err == nil, err
+}
+
+// scanTime 读取数据库时间，支持 time.Time、字符串和字节，nil 返回 valid=false
+func scanTime(v interface{}) (t time.Time, valid bool, err error) {
+	var s string
+	switch value := v.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return value, true, nil
+	case string:
+		s = value
+	case []byte:
+		s = string(value)
+	default:
+		return time.Time{}, false, fmt.Errorf("unsupported type %T", v)
+	}
+
+	loc := timeLocation
+	if loc == nil {
+		loc = time.Local
+	}
+	for _, layout := range scanLayouts {
+		if t, err = time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, err
+}
+
+// JsonDate 日期，json格式默认 2006-01-02，通过 SetDateLayout 设置
+type JsonDate struct {
+	time.Time
+}
+
+// MarshalJSON 序列化json
+func (d JsonDate) MarshalJSON() ([]byte, error) {
+	return marshalTime(d.Time, dateLayout), nil
+}
+
+// UnmarshalJSON 反序列化json，null 和空字符串解析为零值
+func (d *JsonDate) UnmarshalJSON(b []byte) error {
+	value, _, err := unmarshalTime(b, dateLayout)
+	d.Time = value
+	return err
+}
+
+// Value 零值写入 NULL
+func (d JsonDate) Value() (driver.Value, error) {
+	if d.Time.IsZero() {
+		return nil, nil
+	}
+	return d.Time, nil
+}
+
+// Scan 支持 time.Time、字符串和字节
+func (d *JsonDate) Scan(v interface{}) error {
+	value, _, err := scanTime(v)
+	if err != nil {
+		return fmt.Errorf("can not convert %v to JsonDate: %w", v, err)
+	}
+	*d = JsonDate{Time: value}
+	return nil
+}
+
+// GormDataType 数据库类型为 date
+func (JsonDate) GormDataType() string {
+	return "date"
+}
+
+// NullJsonTime 可空的 JsonTime，Valid 为 false 时序列化为 null、写入 NULL
+type NullJsonTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+// MarshalJSON 序列化json
+func (t NullJsonTime) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+	return marshalTime(t.Time, timeLayout), nil
+}
+
+// UnmarshalJSON 反序列化json
+func (t *NullJsonTime) UnmarshalJSON(b []byte) (err error) {
+	t.Time, t.Valid, err = unmarshalTime(b, timeLayout)
+	return err
+}
+
+// Value ...
+func (t NullJsonTime) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.Time, nil
+}
+
+// Scan ...
+func (t *NullJsonTime) Scan(v interface{}) (err error) {
+	t.Time, t.Valid, err = scanTime(v)
+	return err
+}
+
+// NullJsonDate 可空的 JsonDate，Valid 为 false 时序列化为 null、写入 NULL
+type NullJsonDate struct {
+	Time  time.Time
+	Valid bool
+}
+
+// MarshalJSON 序列化json
+func (d NullJsonDate) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return marshalTime(d.Time, dateLayout), nil
+}
+
+// UnmarshalJSON 反序列化json
+func (d *NullJsonDate) UnmarshalJSON(b []byte) (err error) {
+	d.Time, d.Valid, err = unmarshalTime(b, dateLayout)
+	return err
+}
+
+// Value ...
+func (d NullJsonDate) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return d.Time, nil
+}
+
+// Scan ...
+func (d *NullJsonDate) Scan(v interface{}) (err error) {
+	d.Time, d.Valid, err = scanTime(v)
+	return err
+}
+
+// GormDataType 数据库类型为 date
+func (NullJsonDate) GormDataType() string {
+	return "date"
+}
+
+// NullString 可空字符串，NULL 序列化为 null
+type NullString struct {
+	sql.NullString
+}
+
+// MarshalJSON 序列化json
+func (n NullString) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.String)
+}
+
+// UnmarshalJSON 反序列化json
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Valid, &n.String)
+}
+
+// NullInt64 可空整数，NULL 序列化为 null
+type NullInt64 struct {
+	sql.NullInt64
+}
+
+// MarshalJSON 序列化json
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Int64)
+}
+
+// UnmarshalJSON 反序列化json
+func (n *NullInt64) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Valid, &n.Int64)
+}
+
+// NullFloat64 可空浮点数，NULL 序列化为 null
+type NullFloat64 struct {
+	sql.NullFloat64
+}
+
+// MarshalJSON 序列化json
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Float64)
+}
+
+// UnmarshalJSON 反序列化json
+func (n *NullFloat64) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Valid, &n.Float64)
+}
+
+// NullBool 可空布尔值，NULL 序列化为 null
+type NullBool struct {
+	sql.NullBool
+}
+
+// MarshalJSON 序列化json
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Bool)
+}
+
+// UnmarshalJSON 反序列化json
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Valid, &n.Bool)
+}
+
+// marshalNull 无效值序列化为 null
+func marshalNull(valid bool, v interface{}) ([]byte, error) {
+	if !valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalNull null 解析为无效值
+func unmarshalNull(b []byte, valid *bool, v interface{}) error {
+	if bytes.Equal(b, []byte("null")) {
+		*valid = false
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	*valid = true
+	return nil
+}
+
+// JSON 泛型JSON字段，MySQL/SQLite 使用 JSON 类型，Postgres 使用 JSONB
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON 创建JSON字段
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+// MarshalJSON 序列化为 Data 本身
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON 反序列化到 Data
+func (j *JSON[T]) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &j.Data)
+}
+
+// Value 写入json字符串
+func (j JSON[T]) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 支持字符串和字节，NULL 解析为零值
+func (j *JSON[T]) Scan(v interface{}) error {
+	var b []byte
+	switch value := v.(type) {
+	case nil:
+		var zero T
+		j.Data = zero
+		return nil
+	case []byte:
+		b = value
+	case string:
+		b = []byte(value)
+	default:
+		return fmt.Errorf("can not convert %T to JSON", v)
+	}
+	if len(b) == 0 {
+		var zero T
+		j.Data = zero
+		return nil
+	}
+	return json.Unmarshal(b, &j.Data)
+}
+
+// GormDataType 实现 schema.GormDataTypeInterface
+func (JSON[T]) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType 按数据库返回字段类型
+func (JSON[T]) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "JSONB"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
+	default:
+		return "JSON"
+	}
+}
@@ -0,0 +1,69 @@
+package gormx
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testProfile struct {
+	ID       int64                     `json:"id" gorm:"primaryKey"`
+	Tags     JSON[[]string]            `json:"tags"`
+	Settings JSON[map[string]int]      `json:"settings"`
+	Birthday JsonDate                  `json:"birthday"`
+	LoginAt  JsonTime                  `json:"login_at"`
+	LeftAt   NullJsonTime              `json:"left_at"`
+	Nickname NullString                `json:"nickname"`
+	Score    NullInt64                 `json:"score"`
+	Extra    JSON[map[string]struct{}] `json:"extra"`
+}
+
+func TestColumnTypes(t *testing.T) {
+	pool, err := NewDBPool(Driver(DriverSQLite), Name(filepath.Join(t.TempDir(), "types.db")))
+	if err != nil {
+		t.Fatalf("NewDBPool failed: %v", err)
+	}
+	db := pool.GetConn()
+	if err := db.AutoMigrate(&testProfile{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	login := time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)
+	profile := testProfile{
+		Tags:     NewJSON([]string{"a", "b"}),
+		Settings: NewJSON(map[string]int{"x": 1}),
+		Birthday: JsonDate{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local)},
+		LoginAt:  JsonTime{Time: login},
+	}
+	profile.Nickname.String, profile.Nickname.Valid = "tom", true
+	if err := db.Create(&profile).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var got testProfile
+	if err := db.First(&got, profile.ID).Error; err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if len(got.Tags.Data) != 2 || got.Settings.Data["x"] != 1 || !got.LoginAt.Equal(login) || got.LeftAt.Valid {
+		t.Errorf("unexpected scanned profile: %+v", got)
+	}
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"id":1,"tags":["a","b"],"settings":{"x":1},"birthday":"2000-01-02","login_at":"2024-05-06 07:08:09",` +
+		`"left_at":null,"nickname":"tom","score":null,"extra":null}`
+	if string(b) != want {
+		t.Errorf("expected %s, got %s", want, b)
+	}
+
+	var decoded testProfile
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.LeftAt.Valid || decoded.Score.Valid || !decoded.Nickname.Valid || decoded.Birthday.Day() != 2 {
+		t.Errorf("unexpected decoded profile: %+v", decoded)
+	}
+}
@@ -1,22 +1,66 @@
 package jwtx
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go/v4"
 )
 
-var jwtSecret = []byte("9999")
+// defaultSecret 内置默认密钥，仅为兼容旧版本保留，公开仓库中的密钥不能用于认证
+var defaultSecret = []byte("9999")
+
+var (
+	secretMu  sync.RWMutex
+	jwtSecret = defaultSecret
+	secretSet bool // 是否已通过 SetSecret 设置密钥
+)
+
+// ErrInvalidSigningMethod token 的签名算法不是 HS256
+var ErrInvalidSigningMethod = errors.New("invalid signing method")
+
+// SetSecret 设置签名密钥，服务启动时调用，未设置时使用内置默认密钥
+func SetSecret(secret []byte) {
+	if len(secret) == 0 {
+		panic("jwtx: secret must not be empty")
+	}
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	jwtSecret = append([]byte(nil), secret...)
+	secretSet = true
+}
+
+// SecretConfigured 是否已通过 SetSecret 设置密钥
+func SecretConfigured() bool {
+	secretMu.RLock()
+	defer secretMu.RUnlock()
+	return secretSet
+}
+
+// secret 当前签名密钥
+func secret() []byte {
+	secretMu.RLock()
+	defer secretMu.RUnlock()
+	return jwtSecret
+}
 
 // Claims TODO
 type Claims struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Roles    []string `json:"roles,omitempty"` // 角色，用于路由鉴权
 	jwt.StandardClaims
 }
 
 // GenerateToken 生成token
 func GenerateToken(username, password string) (string, error) {
+	return GenerateTokenWithRoles(username, password)
+}
+
+// GenerateTokenWithRoles 生成携带角色的token
+func GenerateTokenWithRoles(username, password string, roles ...string) (string, error) {
 	nowTime := time.Now()
 
 	var expireTime time.Time
@@ -25,23 +69,37 @@ func GenerateToken(username, password string) (string, error) {
 	claims := Claims{
 		username,
 		password,
+		roles,
 		jwt.StandardClaims{
-			ExpiresAt: &jwt.Time{expireTime},
+			ExpiresAt: &jwt.Time{Time: expireTime},
 			Issuer:    "ginx-blog",
 		},
 	}
 
 	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, err := tokenClaims.SignedString(jwtSecret)
+	token, err := tokenClaims.SignedString(secret())
 
 	return token, err
 }
 
-// ParseToken 解析token
+// ParseToken 使用 SetSecret 设置的密钥解析token，只接受 HS256 签名
 func ParseToken(token string) (*Claims, error) {
+	return ParseTokenWithSecret(token, secret())
+}
+
+// ParseTokenWithSecret 使用指定密钥解析token，只接受 HS256 签名
+func ParseTokenWithSecret(token string, secret []byte) (*Claims, error) {
+	var methodErr error
 	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+		if token.Method == nil || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			methodErr = fmt.Errorf("%w: %v", ErrInvalidSigningMethod, token.Header["alg"])
+			return nil, methodErr
+		}
+		return secret, nil
 	})
+	if methodErr != nil {
+		return nil, methodErr
+	}
 
 	if tokenClaims != nil {
 		if claims, ok := tokenClaims.Claims.(*Claims); ok && tokenClaims.Valid {
@@ -0,0 +1,43 @@
+package jwtx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go/v4"
+)
+
+func TestParseToken(t *testing.T) {
+	secret := []byte("test-secret")
+	SetSecret(secret)
+	if !SecretConfigured() {
+		t.Fatal("expected secret to be configured")
+	}
+
+	token, err := GenerateTokenWithRoles("tom", "", "admin")
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	claims, err := ParseToken(token)
+	if err != nil || claims.Username != "tom" || len(claims.Roles) != 1 {
+		t.Fatalf("expected valid claims, got %+v, %v", claims, err)
+	}
+	if _, err := ParseTokenWithSecret(token, defaultSecret); err == nil {
+		t.Error("expected token signed with another secret to be rejected")
+	}
+
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS512, Claims{Roles: []string{"admin"}}).SignedString(secret)
+	if err != nil {
+		t.Fatalf("SignedString failed: %v", err)
+	}
+	if _, err := ParseToken(forged); !errors.Is(err, ErrInvalidSigningMethod) {
+		t.Errorf("expected ErrInvalidSigningMethod for HS512, got %v", err)
+	}
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{Roles: []string{"admin"}}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString failed: %v", err)
+	}
+	if _, err := ParseToken(none); err == nil {
+		t.Error("expected alg none to be rejected")
+	}
+}
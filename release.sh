@@ -149,7 +149,7 @@ create_archive() {
     local file_name=$1
     local version=$2
     local tag=$3
-    local archive_name="pkg-go-${file_name}-${version}"
+    local archive_name="pkg-go-${file_name//\//-}-${version}"
     
     if [[ "$NO_ARCHIVE" == true ]]; then
         log_info "Skipping archive creation (--no-archive specified)"